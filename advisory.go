@@ -0,0 +1,200 @@
+package pub
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrAdvisoryTermMissingID = errors.New("advisories: missing id")
+)
+
+type ErrAdvisoryTermDuplicateID struct {
+	Vocabulary string
+	ID         string
+}
+
+func (e ErrAdvisoryTermDuplicateID) Error() string {
+	return fmt.Sprintf("advisories: %s: id \"%s\" is used by more than one term", e.Vocabulary, e.ID)
+}
+
+type ErrContentWarningUnknown struct {
+	ID string
+}
+
+func (e ErrContentWarningUnknown) Error() string {
+	return fmt.Sprintf("content warning \"%s\" is not declared under advisories.content_warnings in %s", e.ID, BookConfigFileName)
+}
+
+type ErrRatingUnknown struct {
+	ID string
+}
+
+func (e ErrRatingUnknown) Error() string {
+	return fmt.Sprintf("rating \"%s\" is not declared under advisories.ratings in %s", e.ID, BookConfigFileName)
+}
+
+// Advisories declares the content warnings and ratings a [Book] may use.
+// Ratings are listed from the least to the most restrictive.
+type Advisories struct {
+	ContentWarnings []AdvisoryTerm `json:"content_warnings"`
+	Ratings         []AdvisoryTerm `json:"ratings"`
+}
+
+// AdvisoryTerm is a content warning (e.g. "violence") or a rating (e.g. "teen").
+type AdvisoryTerm struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// MinimumAge is the age that readers should be to read content with the rating (for ratings only).
+	MinimumAge int `json:"minimum_age"`
+}
+
+// ContentWarningTerms returns the terms of the book's content warnings.
+func (b Book) ContentWarningTerms() []AdvisoryTerm {
+	return advisoryTerms(b.Advisories.ContentWarnings, b.ContentWarnings)
+}
+
+// RatingTerm returns the term of the book's rating, or nil.
+func (b Book) RatingTerm() *AdvisoryTerm {
+	return advisoryTerm(b.Advisories.Ratings, b.Rating)
+}
+
+// ContentWarningTerms returns the terms of the chapter's content warnings.
+func (c Chapter) ContentWarningTerms() []AdvisoryTerm {
+	return advisoryTerms(c.advisories().ContentWarnings, c.ContentWarnings)
+}
+
+// RatingTerm returns the term of the chapter's rating, or nil.
+func (c Chapter) RatingTerm() *AdvisoryTerm {
+	return advisoryTerm(c.advisories().Ratings, c.Rating)
+}
+
+// HasAdvisories reports whether the chapter has content warnings or a rating.
+func (c Chapter) HasAdvisories() bool {
+	return len(c.ContentWarnings) > 0 || c.Rating != ""
+}
+
+func (c Chapter) advisories() Advisories {
+	if c.Book == nil {
+		return Advisories{}
+	}
+
+	return c.Book.Advisories
+}
+
+// advisoryTerms returns the term of each of ids from vocabulary.
+func advisoryTerms(vocabulary []AdvisoryTerm, ids []string) []AdvisoryTerm {
+	terms := make([]AdvisoryTerm, 0, len(ids))
+	for _, id := range ids {
+		terms = append(terms, *advisoryTerm(vocabulary, id))
+	}
+
+	return terms
+}
+
+// advisoryTerm returns the term with the given id from vocabulary, or nil if id
+// is empty.
+func advisoryTerm(vocabulary []AdvisoryTerm, id string) *AdvisoryTerm {
+	if id == "" {
+		return nil
+	}
+
+	if i := slices.IndexFunc(vocabulary, func(t AdvisoryTerm) bool { return t.ID == id }); i >= 0 {
+		return &vocabulary[i]
+	}
+
+	return &AdvisoryTerm{ID: id, Name: id}
+}
+
+func normalizeAdvisoryID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// normalize lowercases the IDs of the vocabulary's terms, and fills in their names.
+func (a *Advisories) normalize() {
+	for _, terms := range [][]AdvisoryTerm{a.ContentWarnings, a.Ratings} {
+		for i := range terms {
+			terms[i].ID = normalizeAdvisoryID(terms[i].ID)
+			if terms[i].Name == "" {
+				terms[i].Name = terms[i].ID
+			}
+		}
+	}
+}
+
+// normalizeAdvisoryValues lowercases content warnings and a rating, as their terms' IDs are.
+func normalizeAdvisoryValues(warnings []string, rating *string) {
+	for i, id := range warnings {
+		warnings[i] = normalizeAdvisoryID(id)
+	}
+
+	*rating = normalizeAdvisoryID(*rating)
+}
+
+func (a Advisories) validate(v *validator, loc location) {
+	validateAdvisoryTerms(v, loc, "content_warnings", a.ContentWarnings)
+	validateAdvisoryTerms(v, loc, "ratings", a.Ratings)
+}
+
+func validateAdvisoryTerms(v *validator, loc location, vocabulary string, terms []AdvisoryTerm) {
+	seen := make(map[string]bool)
+	for i, term := range terms {
+		if term.ID == "" {
+			v.error(loc.index(vocabulary, i).field("id"), ErrAdvisoryTermMissingID)
+			continue
+		}
+
+		if seen[term.ID] {
+			v.error(loc.index(vocabulary, i).field("id"), ErrAdvisoryTermDuplicateID{Vocabulary: vocabulary, ID: term.ID})
+		}
+		seen[term.ID] = true
+	}
+}
+
+// validateValues checks the content warnings and rating at loc against the vocabulary.
+func (a Advisories) validateValues(v *validator, loc location, warnings []string, rating string) {
+	for i, id := range warnings {
+		if len(a.ContentWarnings) > 0 && !slices.ContainsFunc(a.ContentWarnings, func(t AdvisoryTerm) bool { return t.ID == id }) {
+			v.error(loc.index("content_warnings", i), ErrContentWarningUnknown{ID: id})
+		}
+	}
+
+	if rating != "" && len(a.Ratings) > 0 && !slices.ContainsFunc(a.Ratings, func(t AdvisoryTerm) bool { return t.ID == rating }) {
+		v.error(loc.field("rating"), ErrRatingUnknown{ID: rating})
+	}
+}
+
+// aggregateAdvisories adds the content warnings of the book's chapters to the
+// book's, and raises its rating to the most restrictive of theirs.
+func (b *Book) aggregateAdvisories() {
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		for _, id := range chapter.ContentWarnings {
+			if !slices.Contains(b.ContentWarnings, id) {
+				b.ContentWarnings = append(b.ContentWarnings, id)
+			}
+		}
+
+		if chapter.Rating == "" {
+			continue
+		}
+
+		if b.Rating == "" || b.ratingIndex(chapter.Rating) > b.ratingIndex(b.Rating) {
+			b.Rating = chapter.Rating
+		}
+	}
+
+	if vocabulary := b.Advisories.ContentWarnings; len(vocabulary) > 0 {
+		slices.SortStableFunc(b.ContentWarnings, func(x, y string) int {
+			return slices.IndexFunc(vocabulary, func(t AdvisoryTerm) bool { return t.ID == x }) - slices.IndexFunc(vocabulary, func(t AdvisoryTerm) bool { return t.ID == y })
+		})
+	}
+}
+
+// ratingIndex returns the position of the rating with the given id, or -1.
+func (b Book) ratingIndex(id string) int {
+	return slices.IndexFunc(b.Advisories.Ratings, func(t AdvisoryTerm) bool { return t.ID == id })
+}
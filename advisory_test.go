@@ -0,0 +1,133 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// advisoriesFS returns a file system with a book at "book" of a chapter per frontMatters.
+func advisoriesFS(pubYML string, frontMatters ...string) fstest.MapFS {
+	fsys := fstest.MapFS{"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n" + pubYML)}}
+
+	var nav string
+	for i, frontMatter := range frontMatters {
+		name := string(rune('a'+i)) + ".md"
+		nav += "- content_file_name: " + name + "\n"
+		fsys["book/chapters/"+name] = &fstest.MapFile{Data: []byte("---\n" + frontMatter + "---\nChapter\n")}
+	}
+	fsys["book/nav.yml"] = &fstest.MapFile{Data: []byte(nav)}
+
+	return fsys
+}
+
+const advisoriesVocabulary = `advisories:
+  content_warnings:
+    - id: Violence
+    - id: language
+      name: Strong language
+  ratings:
+    - id: general
+    - id: teen
+      minimum_age: 13
+    - id: mature
+      minimum_age: 17
+`
+
+func TestAggregateAdvisories(t *testing.T) {
+	tests := []struct {
+		name         string
+		pubYML       string
+		frontMatters []string
+		wantWarnings []string
+		wantRating   string
+	}{
+		{
+			"vocabulary order",
+			advisoriesVocabulary + "content_warnings: [language]\n",
+			[]string{"content_warnings: [LANGUAGE]\n", "content_warnings: [violence]\n"},
+			[]string{"violence", "language"},
+			"",
+		},
+		{
+			"most restrictive rating",
+			advisoriesVocabulary + "rating: general\n",
+			[]string{"rating: Mature\n", "rating: teen\n"},
+			nil,
+			"mature",
+		},
+		{
+			"book rating kept",
+			advisoriesVocabulary + "rating: mature\n",
+			[]string{"rating: teen\n"},
+			nil,
+			"mature",
+		},
+		{
+			"no vocabulary",
+			"",
+			[]string{"content_warnings: [gore]\nrating: r\n", "content_warnings: [gore, fear]\nrating: pg\n"},
+			[]string{"gore", "fear"},
+			"r",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book, err := NewBookFS(advisoriesFS(tt.pubYML, tt.frontMatters...), "book")
+			if err != nil {
+				t.Fatalf("NewBookFS() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(book.ContentWarnings, tt.wantWarnings) || book.Rating != tt.wantRating {
+				t.Errorf("ContentWarnings, Rating = %v, %q, want %v, %q", book.ContentWarnings, book.Rating, tt.wantWarnings, tt.wantRating)
+			}
+		})
+	}
+}
+
+func TestAdvisoryTerms(t *testing.T) {
+	book, err := NewBookFS(advisoriesFS(advisoriesVocabulary, "content_warnings: [language, violence]\nrating: teen\n"), "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	chapter := book.Chapters[0]
+	want := []AdvisoryTerm{{ID: "language", Name: "Strong language"}, {ID: "violence", Name: "violence"}}
+	if got := chapter.ContentWarningTerms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ContentWarningTerms() = %+v, want %+v", got, want)
+	}
+	if got := chapter.RatingTerm(); got == nil || got.ID != "teen" || got.MinimumAge != 13 {
+		t.Errorf("RatingTerm() = %+v, want teen", got)
+	}
+	if !chapter.HasAdvisories() {
+		t.Errorf("HasAdvisories() = false")
+	}
+	if got := book.RatingTerm(); got == nil || got.ID != "teen" {
+		t.Errorf("book RatingTerm() = %+v, want teen", got)
+	}
+}
+
+func TestAdvisoriesErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		pubYML       string
+		frontMatters []string
+		want         error
+	}{
+		{"unknown book warning", advisoriesVocabulary + "content_warnings: [gore]\n", nil, ErrContentWarningUnknown{ID: "gore"}},
+		{"unknown chapter rating", advisoriesVocabulary, []string{"rating: adult\n"}, ErrRatingUnknown{ID: "adult"}},
+		{"missing term id", "advisories:\n  ratings:\n    - name: Teen\n", nil, ErrAdvisoryTermMissingID},
+		{"duplicate term id", "advisories:\n  content_warnings:\n    - id: gore\n    - id: Gore\n", nil, ErrAdvisoryTermDuplicateID{Vocabulary: "content_warnings", ID: "gore"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(advisoriesFS(tt.pubYML, tt.frontMatters...), "book")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NewBookFS() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,65 @@
 package pub
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	AssetSidecarFileExtension = ".yml"
 )
 
 var (
 	ErrAssetMissingObjects        = errors.New("asset: could not find objects/descriptors")
 	ErrAssetDescriptorMissingName = errors.New("asset descriptor: missing Name")
+
+	// AssetFormatPreference is the default order of the formats of an [Asset].
+	// Other formats come after, sorted by name.
+	AssetFormatPreference = []string{
+		"image/avif",
+		"image/webp",
+		"image/jxl",
+		"image/png",
+		"image/jpeg",
+		"image/gif",
+		"video/webm",
+		"video/mp4",
+		"audio/ogg",
+		"audio/mpeg",
+		"font/woff2",
+		"font/woff",
+		"font/otf",
+		"font/ttf",
+	}
 )
 
+type ErrAssetSidecarUnknownObject struct {
+	AssetName  string
+	ObjectName string
+}
+
+func (e ErrAssetSidecarUnknownObject) Error() string {
+	return fmt.Sprintf("asset \"%s\": order lists \"%s\", which is not a file of the asset", e.AssetName, e.ObjectName)
+}
+
+type ErrAssetMismatchedContentType struct {
+	DescriptorName string
+	DeclaredFormat string
+	DetectedFormat string
+}
+
+func (e ErrAssetMismatchedContentType) Error() string {
+	return fmt.Sprintf("asset descriptor \"%s\" mismatched file type: its extension is for \"%s\" but its contents are \"%s\"", e.DescriptorName, e.DeclaredFormat, e.DetectedFormat)
+}
+
 type ErrAssetMismatchedDescriptorType struct {
 	AssetType      string
 	DescriptorName string
@@ -21,10 +71,24 @@ func (e ErrAssetMismatchedDescriptorType) Error() string {
 }
 
 // Asset represents a media element such as an image or video. Supports specifying multiple [AssetDescriptor]s which will be used as fallback formats (in the specified order) when the asset is not supported by the application.
+//
+// Files sharing a base name and media type (e.g. "cover.avif" and "cover.jpg")
+// are one asset (e.g. "cover"), described by a sidecar (e.g. "cover.jpg.yml").
 type Asset struct {
-	Objects         []AssetDescriptor
-	AlternativeText string
-	Caption         Content
+	Name            string            `json:"name"`
+	Objects         []AssetDescriptor `json:"objects"`
+	AlternativeText string            `json:"alternative_text"`
+	Caption         Content           `json:"caption"`
+
+	loc   location
+	order []string
+}
+
+// assetSidecar is the metadata of an [Asset] that is read from a sidecar file.
+type assetSidecar struct {
+	AlternativeText string   `json:"alternative_text"`
+	Caption         Content  `json:"caption"`
+	Order           []string `json:"order"`
 }
 
 func (a *Asset) MainDescriptor() AssetDescriptor {
@@ -48,13 +112,37 @@ func (a *Asset) OtherDescriptors() []AssetDescriptor {
 }
 
 func (a *Asset) EnsureValid() error {
+	return validateWith(func(v *validator) {
+		a.validate(v, nil)
+	})
+}
+
+func (a *Asset) validate(v *validator, loc location) {
 	if len(a.Objects) == 0 {
-		return ErrAssetMissingObjects
+		v.error(loc, ErrAssetMissingObjects)
+		return
+	}
+
+	for i, name := range a.order {
+		if !slices.ContainsFunc(a.Objects, func(d AssetDescriptor) bool { return path.Base(d.Name) == name }) {
+			v.error(loc.index("order", i), ErrAssetSidecarUnknownObject{AssetName: a.Name, ObjectName: name})
+		}
+	}
+
+	for i, d := range a.Objects {
+		declared, _, _ := strings.Cut(d.declared, "/")
+		if declared != "" && d.Type != "" && declared != d.Type {
+			v.error(loc.index("objects", i), ErrAssetMismatchedContentType{
+				DescriptorName: d.Name,
+				DeclaredFormat: d.declared,
+				DetectedFormat: d.Format,
+			})
+		}
 	}
 
 	main := a.MainDescriptor()
-	if err := main.CheckValid(); err != nil {
-		return err
+	if !v.check(loc.index("objects", 0), main.CheckValid()) {
+		return
 	}
 	mainType := main.Type
 
@@ -62,23 +150,28 @@ func (a *Asset) EnsureValid() error {
 	for i := range otherDescriptors {
 		o := &otherDescriptors[i]
 
+		if !v.check(loc.index("objects", i+1), o.CheckValid()) {
+			continue
+		}
+
 		if mainType != "" && o.Type != "" && o.Type != mainType {
-			return ErrAssetMismatchedDescriptorType{
+			v.error(loc.index("objects", i+1), ErrAssetMismatchedDescriptorType{
 				AssetType:      mainType,
 				DescriptorType: o.Type,
 				DescriptorName: o.Name,
-			}
+			})
 		}
 	}
-
-	return nil
 }
 
 // AssetDescriptor represents an individual file format of a media element.
+// Format is its MIME type (e.g. "image/webp"), and Type the top-level one.
 type AssetDescriptor struct {
-	Name   string
-	Type   string
-	Format string
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Format string `json:"format"`
+
+	declared string
 }
 
 func (a *AssetDescriptor) CheckValid() error {
@@ -88,3 +181,94 @@ func (a *AssetDescriptor) CheckValid() error {
 
 	return nil
 }
+
+// sortAssetDescriptors sorts descriptors by the names in order, and then by
+// [AssetFormatPreference].
+func sortAssetDescriptors(descriptors []AssetDescriptor, order []string) {
+	rank := func(d AssetDescriptor) int {
+		if i := slices.Index(order, path.Base(d.Name)); i >= 0 {
+			return i - len(order)
+		}
+
+		if i := slices.Index(AssetFormatPreference, d.Format); i >= 0 {
+			return i
+		}
+
+		return len(AssetFormatPreference)
+	}
+
+	slices.SortStableFunc(descriptors, func(a, b AssetDescriptor) int {
+		if c := rank(a) - rank(b); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// detectAssetFormat returns the MIME type of the file at name in fsys, from its
+// contents or else its extension.
+func detectAssetFormat(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	format := sniffFormat(head)
+	switch format {
+	case "application/octet-stream", "text/plain", "text/xml":
+		if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+			format = byExtension
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(format)
+	if err != nil {
+		return format, nil
+	}
+
+	return mediaType, nil
+}
+
+// declaredAssetFormat returns the MIME type of the extension of name, or "" if unknown.
+func declaredAssetFormat(name string) string {
+	mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(path.Ext(name)))
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+// sniffFormat extends [http.DetectContentType] with formats it does not recognize.
+func sniffFormat(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		switch string(head[8:12]) {
+		case "avif", "avis":
+			return "image/avif"
+		case "heic", "heix":
+			return "image/heic"
+		}
+	}
+
+	if bytes.HasPrefix(head, []byte("\xff\x0a")) || bytes.HasPrefix(head, []byte("\x00\x00\x00\x0cJXL \x0d\x0a\x87\x0a")) {
+		return "image/jxl"
+	}
+
+	format := http.DetectContentType(head)
+	if strings.HasPrefix(format, "text/xml") || strings.HasPrefix(format, "text/plain") {
+		if bytes.Contains(head, []byte("<svg")) {
+			return "image/svg+xml"
+		}
+	}
+
+	return format
+}
@@ -0,0 +1,149 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+var (
+	pngData  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegData = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	webmData = []byte("\x1a\x45\xdf\xa3\x01\x00\x00\x00\x00\x00\x00\x1f\x42\x86\x81\x01webm")
+	avifData = []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00")
+)
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"png", pngData, "image/png"},
+		{"jpeg", jpegData, "image/jpeg"},
+		{"avif", avifData, "image/avif"},
+		{"jxl", []byte("\xff\x0a\x00"), "image/jxl"},
+		{"svg", []byte("<?xml version=\"1.0\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), "image/svg+xml"},
+		{"text", []byte("hello"), "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffFormat(tt.head); got != tt.want {
+				t.Errorf("sniffFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortAssetDescriptors(t *testing.T) {
+	descriptors := func() []AssetDescriptor {
+		return []AssetDescriptor{
+			{Name: "cover.jpg", Format: "image/jpeg"},
+			{Name: "cover.bmp", Format: "image/bmp"},
+			{Name: "cover.avif", Format: "image/avif"},
+			{Name: "cover.png", Format: "image/png"},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		order []string
+		want  []string
+	}{
+		{"format preference", nil, []string{"cover.avif", "cover.png", "cover.jpg", "cover.bmp"}},
+		{"order", []string{"cover.jpg", "cover.bmp"}, []string{"cover.jpg", "cover.bmp", "cover.avif", "cover.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := descriptors()
+			sortAssetDescriptors(got, tt.order)
+
+			var names []string
+			for _, d := range got {
+				names = append(names, d.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("sortAssetDescriptors() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":                     {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+		"book/assets/cover.jpg":            {Data: jpegData},
+		"book/assets/cover.png":            {Data: pngData},
+		"book/assets/cover.jpg.yml":        {Data: []byte("alternative_text: A cover\norder: [cover.jpg]\n")},
+		"book/assets/images/map.avif":      {Data: avifData},
+		"book/assets/.hidden/ignored.png":  {Data: pngData},
+		"book/assets/fonts/.DS_Store":      {Data: []byte("x")},
+		"book/assets/fonts/LICENSE.txt":    {Data: []byte("license")},
+		"book/assets/images/map.png.notes": {Data: []byte("notes")},
+	}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	want := []Asset{
+		{Name: "cover", AlternativeText: "A cover", Objects: []AssetDescriptor{
+			{Name: "cover.jpg", Type: "image", Format: "image/jpeg"},
+			{Name: "cover.png", Type: "image", Format: "image/png"},
+		}},
+		{Name: "fonts/LICENSE", Objects: []AssetDescriptor{{Name: "fonts/LICENSE.txt", Type: "text", Format: "text/plain"}}},
+		{Name: "images/map", Objects: []AssetDescriptor{{Name: "images/map.avif", Type: "image", Format: "image/avif"}}},
+		{Name: "images/map.png", Objects: []AssetDescriptor{{Name: "images/map.png.notes", Type: "text", Format: "text/plain"}}},
+	}
+
+	if len(book.Assets) != len(want) {
+		t.Fatalf("Assets = %+v, want %d assets", book.Assets, len(want))
+	}
+	for i, asset := range book.Assets {
+		if asset.Name != want[i].Name || asset.AlternativeText != want[i].AlternativeText {
+			t.Errorf("Assets[%d] = %q (%q), want %q (%q)", i, asset.Name, asset.AlternativeText, want[i].Name, want[i].AlternativeText)
+		}
+		if len(asset.Objects) != len(want[i].Objects) {
+			t.Errorf("Assets[%d].Objects = %+v, want %+v", i, asset.Objects, want[i].Objects)
+			continue
+		}
+		for j, object := range asset.Objects {
+			w := want[i].Objects[j]
+			if object.Name != w.Name || object.Type != w.Type || object.Format != w.Format {
+				t.Errorf("Assets[%d].Objects[%d] = %+v, want %+v", i, j, object, w)
+			}
+		}
+	}
+}
+
+func TestNewAssetsMismatchedTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string][]byte
+		want  error
+	}{
+		{"content does not match extension", map[string][]byte{"clip.webm": pngData}, ErrAssetMismatchedContentType{}},
+		{"same name, different media types", map[string][]byte{"cover.png": pngData, "cover.webm": webmData}, ErrAssetMismatchedDescriptorType{}},
+		{"unknown sidecar order", map[string][]byte{"cover.png": pngData, "cover.png.yml": []byte("order: [cover.gif]\n")}, ErrAssetSidecarUnknownObject{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")}}
+			for name, data := range tt.files {
+				fsys["book/assets/"+name] = &fstest.MapFile{Data: data}
+			}
+
+			_, err := NewBookFS(fsys, "book")
+
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("NewBookFS() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package pub
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	// AuthorsNoteBeforeSuffix names the sidecar file of [Chapter.AuthorsNotePrefix].
+	AuthorsNoteBeforeSuffix = ".note-before"
+	// AuthorsNoteAfterSuffix names the sidecar file of [Chapter.AuthorsNoteSuffix].
+	AuthorsNoteAfterSuffix = ".note-after"
+
+	// AuthorsNoteBeforeStart and AuthorsNoteBeforeEnd delimit [Chapter.AuthorsNotePrefix].
+	AuthorsNoteBeforeStart = "<!-- note-before -->"
+	AuthorsNoteBeforeEnd   = "<!-- /note-before -->"
+	// AuthorsNoteAfterStart and AuthorsNoteAfterEnd delimit [Chapter.AuthorsNoteSuffix].
+	AuthorsNoteAfterStart = "<!-- note-after -->"
+	AuthorsNoteAfterEnd   = "<!-- /note-after -->"
+)
+
+type ErrAuthorsNoteUnterminated struct {
+	Start string
+	End   string
+}
+
+func (e ErrAuthorsNoteUnterminated) Error() string {
+	return fmt.Sprintf("author's note section starting with \"%s\" is not closed by a \"%s\" line", e.Start, e.End)
+}
+
+// IncludesAuthorsNotes reports whether authors' notes belong in the output format.
+func (b Book) IncludesAuthorsNotes(format string) bool {
+	return !slices.ContainsFunc(b.ExcludeAuthorsNotes, func(f string) bool {
+		return strings.EqualFold(strings.TrimSpace(f), format)
+	})
+}
+
+// isAuthorsNoteFile reports whether name is the sidecar file of an author's note.
+func isAuthorsNoteFile(name string) bool {
+	base := strings.TrimSuffix(name, path.Ext(name))
+
+	return strings.HasSuffix(base, AuthorsNoteBeforeSuffix) || strings.HasSuffix(base, AuthorsNoteAfterSuffix)
+}
+
+// loadAuthorsNoteFiles sets the authors' notes of chapter from its sidecar files.
+func loadAuthorsNoteFiles(fsys fs.FS, chapter *Chapter, contentPath string) error {
+	ext := path.Ext(contentPath)
+	base := strings.TrimSuffix(contentPath, ext)
+
+	notes := []struct {
+		suffix  string
+		content *Content
+	}{
+		{AuthorsNoteBeforeSuffix, &chapter.AuthorsNotePrefix},
+		{AuthorsNoteAfterSuffix, &chapter.AuthorsNoteSuffix},
+	}
+	for _, note := range notes {
+		raw, err := fs.ReadFile(fsys, base+note.suffix+ext)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		*note.content = Content{Raw: raw}
+	}
+
+	return nil
+}
+
+// splitAuthorsNotes copies r to body, except for the authors' notes sections,
+// which are returned as before and after.
+func splitAuthorsNotes(r io.Reader, body io.Writer) (before, after []byte, size int, err error) {
+	var section *[]byte
+	var end, fence string
+
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, nil, 0, readErr
+		}
+
+		trimmed := strings.TrimSpace(string(line))
+		inCode := fence != ""
+		fence = codeFence(fence, string(line))
+		inCode = inCode || fence != ""
+
+		switch {
+		case section != nil && trimmed == end && !inCode:
+			section = nil
+		case section != nil:
+			*section = append(*section, line...)
+		case trimmed == AuthorsNoteBeforeStart && !inCode:
+			section, end = &before, AuthorsNoteBeforeEnd
+			before = []byte{}
+		case trimmed == AuthorsNoteAfterStart && !inCode:
+			section, end = &after, AuthorsNoteAfterEnd
+			after = []byte{}
+		default:
+			n, err := body.Write(line)
+			size += n
+			if err != nil {
+				return nil, nil, 0, err
+			}
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	if section != nil {
+		start := AuthorsNoteBeforeStart
+		if end == AuthorsNoteAfterEnd {
+			start = AuthorsNoteAfterStart
+		}
+		return nil, nil, 0, ErrAuthorsNoteUnterminated{Start: start, End: end}
+	}
+
+	return before, after, size, nil
+}
+
+// codeFence returns the fence (e.g. "```") of the code block open after line.
+func codeFence(fence, line string) string {
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return fence
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if fence != "" {
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			return ""
+		}
+		return fence
+	}
+
+	for _, c := range "`~" {
+		run := len(trimmed) - len(strings.TrimLeft(trimmed, string(c)))
+		if run < 3 {
+			continue
+		}
+
+		// the info string of a backtick fence cannot contain backticks
+		if c == '`' && strings.ContainsRune(trimmed[run:], '`') {
+			return ""
+		}
+		return trimmed[:run]
+	}
+
+	return ""
+}
+
+// scanAuthorsNotes reads the authors' notes of the file name after offset bytes,
+// and writes the rest of the file to w. size is the number of bytes written.
+func scanAuthorsNotes(fsys fs.FS, name string, offset int, w io.Writer) (before, after []byte, size int, err error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer f.Close()
+
+	if _, err := io.CopyN(io.Discard, f, int64(offset)); err != nil {
+		return nil, nil, 0, err
+	}
+
+	return splitAuthorsNotes(f, w)
+}
+
+// setAuthorsNoteSections sets the authors' notes of chapter to its sections, if any.
+func setAuthorsNoteSections(chapter *Chapter, before, after []byte) {
+	if before != nil {
+		chapter.AuthorsNotePrefix = Content{Raw: bytes.TrimSpace(before)}
+	}
+
+	if after != nil {
+		chapter.AuthorsNoteSuffix = Content{Raw: bytes.TrimSpace(after)}
+	}
+}
@@ -0,0 +1,143 @@
+package pub
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSplitAuthorsNotes(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            string
+		body          string
+		before, after *string
+		wantErr       error
+	}{
+		{
+			name: "no notes",
+			in:   "Text.\n",
+			body: "Text.\n",
+		},
+		{
+			name:   "before and after",
+			in:     "<!-- note-before -->\nHello.\n<!-- /note-before -->\nText.\n<!-- note-after -->\nBye.\n<!-- /note-after -->\n",
+			body:   "Text.\n",
+			before: ptr("Hello.\n"),
+			after:  ptr("Bye.\n"),
+		},
+		{
+			name:  "empty section",
+			in:    "Text.\n<!-- note-after -->\n<!-- /note-after -->\n",
+			body:  "Text.\n",
+			after: ptr(""),
+		},
+		{
+			name:   "indented markers",
+			in:     "  <!-- note-before -->  \nHello.\n\t<!-- /note-before -->\nText.",
+			body:   "Text.",
+			before: ptr("Hello.\n"),
+		},
+		{
+			name:   "crlf",
+			in:     "<!-- note-before -->\r\nHello.\r\n<!-- /note-before -->\r\nText.\r\n",
+			body:   "Text.\r\n",
+			before: ptr("Hello.\r\n"),
+		},
+		{
+			name: "markers within a line",
+			in:   "See <!-- note-after --> here.\n",
+			body: "See <!-- note-after --> here.\n",
+		},
+		{
+			name: "fenced code",
+			in:   "```md\n<!-- note-before -->\n<!-- /note-before -->\n```\nText.\n",
+			body: "```md\n<!-- note-before -->\n<!-- /note-before -->\n```\nText.\n",
+		},
+		{
+			name:  "tilde fence in a note",
+			in:    "Text.\n<!-- note-after -->\n~~~~\n<!-- /note-after -->\n~~~\n~~~~~\nBye.\n<!-- /note-after -->\n",
+			body:  "Text.\n",
+			after: ptr("~~~~\n<!-- /note-after -->\n~~~\n~~~~~\nBye.\n"),
+		},
+		{
+			name:   "closed fence",
+			in:     "```\ncode\n```\n<!-- note-before -->\nHello.\n<!-- /note-before -->\n",
+			body:   "```\ncode\n```\n",
+			before: ptr("Hello.\n"),
+		},
+		{
+			name: "fence of a different character",
+			in:   "~~~\n```\n<!-- note-before -->\n~~~\n",
+			body: "~~~\n```\n<!-- note-before -->\n~~~\n",
+		},
+		{
+			name:   "inline code is not a fence",
+			in:     "```code```\n<!-- note-before -->\nHello.\n<!-- /note-before -->\n",
+			body:   "```code```\n",
+			before: ptr("Hello.\n"),
+		},
+		{
+			name:   "indented code is not a fence",
+			in:     "    ```\n<!-- note-before -->\nHello.\n<!-- /note-before -->\n",
+			body:   "    ```\n",
+			before: ptr("Hello.\n"),
+		},
+		{
+			name:    "unterminated",
+			in:      "<!-- note-before -->\nHello.\n",
+			wantErr: ErrAuthorsNoteUnterminated{Start: AuthorsNoteBeforeStart, End: AuthorsNoteBeforeEnd},
+		},
+		{
+			name:    "mismatched end",
+			in:      "<!-- note-after -->\nBye.\n<!-- /note-before -->\n",
+			wantErr: ErrAuthorsNoteUnterminated{Start: AuthorsNoteAfterStart, End: AuthorsNoteAfterEnd},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			before, after, size, err := splitAuthorsNotes(strings.NewReader(tt.in), &body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("splitAuthorsNotes() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if body.String() != tt.body || size != len(tt.body) {
+				t.Errorf("body = %q (size %d), want %q", body.String(), size, tt.body)
+			}
+			if !equalNote(before, tt.before) {
+				t.Errorf("before = %q, want %v", before, describeNote(tt.before))
+			}
+			if !equalNote(after, tt.after) {
+				t.Errorf("after = %q, want %v", after, describeNote(tt.after))
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// equalNote reports whether note is want, where nil means no section.
+func equalNote(got []byte, want *string) bool {
+	if want == nil {
+		return got == nil
+	}
+
+	return got != nil && string(got) == *want
+}
+
+func describeNote(note *string) string {
+	if note == nil {
+		return "no section"
+	}
+
+	return strconv.Quote(*note)
+}
@@ -3,9 +3,13 @@ package pub
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
+	"time"
 )
 
 var (
@@ -29,34 +33,72 @@ func (e ErrBookEmptyTag) Error() string {
 
 // Book represents a written work, which generally has an ordered list of 1 or more [Chapter]s.
 type Book struct {
-	UniqueID           string         `json:"unique_id"`
-	Title              string         `json:"title"`
-	Subtitle           string         `json:"subtitle"`
-	TitlesAlternate    []string       `json:"titles_alternate"`
-	Description        string         `json:"description"`
-	Tagline            string         `json:"tagline"`
-	Content            Content        `json:"content"`
-	Authors            []Profile      `json:"authors"`
-	Contributors       []Profile      `json:"contributors"`
-	Publishers         []Profile      `json:"publishers"`
-	Tags               []string       `json:"tags"`
-	Status             Status         `json:"status"`
-	Series             []Series       `json:"series"`
-	Edition            string         `json:"edition"`
-	URL                string         `json:"url"`
-	LanguageCode       string         `json:"language_code"`
-	DatePublishedStart *DateTime      `json:"date_published_start"`
-	DatePublishedEnd   *DateTime      `json:"date_published_end"`
-	LinksFunding       []Reference    `json:"links_funding"`
-	LinksMirrors       []Reference    `json:"links_mirrors"`
-	LinksOther         []Reference    `json:"links_other"`
-	Assets             []Asset        `json:"assets"`
-	IDs                map[string]any `json:"ids"`
-	Copyright          Copyright      `json:"copyright"`
-	Chapters           []Chapter      `json:"chapters"`
-	Extra              map[string]any `json:"extra"`
-
-	InputPath string
+	Schema              int                `json:"schema"`
+	UniqueID            string             `json:"unique_id"`
+	Title               string             `json:"title"`
+	Subtitle            string             `json:"subtitle"`
+	TitlesAlternate     []string           `json:"titles_alternate"`
+	Description         string             `json:"description"`
+	Tagline             string             `json:"tagline"`
+	Content             Content            `json:"content"`
+	Authors             []Profile          `json:"authors"`
+	Contributors        []Profile          `json:"contributors"`
+	Publishers          []Profile          `json:"publishers"`
+	ProfilesRegistry    map[string]Profile `json:"profiles_registry"`
+	Tags                []string           `json:"tags"`
+	Status              Status             `json:"status"`
+	ContentWarnings     []string           `json:"content_warnings"`
+	Rating              string             `json:"rating"`
+	Advisories          Advisories         `json:"advisories"`
+	Series              []Series           `json:"series"`
+	Edition             string             `json:"edition"`
+	URL                 string             `json:"url"`
+	LanguageCode        string             `json:"language_code"`
+	TimeZone            string             `json:"time_zone"`
+	Numbering           Numbering          `json:"numbering"`
+	DatePublishedStart  *DateTime          `json:"date_published_start"`
+	DatePublishedEnd    *DateTime          `json:"date_published_end"`
+	LinksFunding        []Reference        `json:"links_funding"`
+	LinksMirrors        []Reference        `json:"links_mirrors"`
+	LinksOther          []Reference        `json:"links_other"`
+	Assets              []Asset            `json:"assets"`
+	IDs                 Identifiers        `json:"ids"`
+	Copyright           Copyright          `json:"copyright"`
+	Chapters            []Chapter          `json:"chapters"`
+	SplitVolumes        bool               `json:"split_volumes"`
+	ExcludeAuthorsNotes []string           `json:"exclude_authors_notes"`
+	Taxonomies          []Taxonomy         `json:"taxonomies"`
+	Extra               map[string]any     `json:"extra"`
+
+	InputPath    string `json:"-"`
+	BuildProfile string `json:"-"`
+
+	// ReadingOrders are defined in nav.yml and reading_orders.yml, not pub.yml.
+	ReadingOrders []ReadingOrder `json:"-"`
+
+	// Translations are the editions of the book in other languages, from translations/.
+	Translations []Book `json:"-"`
+
+	// ParentBook is the book that a volume was split from, or nil.
+	ParentBook *Book `json:"-"`
+
+	// Languages are the editions of the book in each of its languages.
+	Languages []Language `json:"-"`
+
+	// Withheld holds the unique IDs of the draft and scheduled chapters left out.
+	Withheld []string `json:"-"`
+
+	root          string
+	originalRoot  string
+	fsys          fs.FS
+	options       bookOptions
+	loc           location
+	profilesPath  string
+	generatedUUID bool
+	sourceSchema  int
+	deferred      []deferredError
+	sources       map[string]*yamlSource
+	diagnostics   Diagnostics
 }
 
 func (b *Book) SetInputPath(inputPath string) error {
@@ -69,12 +111,27 @@ func (b *Book) SetInputPath(inputPath string) error {
 	return nil
 }
 
+// inputPathOf returns the path reported for name, a path in the book's file system.
+func (b *Book) inputPathOf(name string) string {
+	return inputPathIn(b.options.baseDir, name)
+}
+
+// addSource registers the YAML document raw of type schema, starting at line
+// lineOffset+1 of inputPath, so that problems are reported at their position.
+func (b *Book) addSource(inputPath string, raw []byte, lineOffset int, schema reflect.Type) {
+	if b.sources == nil {
+		b.sources = make(map[string]*yamlSource)
+	}
+
+	b.sources[inputPath] = &yamlSource{raw: raw, lineOffset: lineOffset, schema: schema}
+}
+
 func (b *Book) SetDatePublishedStartFromString(input string) error {
 	t, err := dateFromString(input)
 	if err != nil {
 		return err
 	}
-	*b.DatePublishedStart = t
+	b.DatePublishedStart = &t
 
 	return nil
 }
@@ -84,11 +141,27 @@ func (b *Book) SetDatePublishedEndFromString(input string) error {
 	if err != nil {
 		return err
 	}
-	*b.DatePublishedEnd = t
+	b.DatePublishedEnd = &t
 
 	return nil
 }
 
+// setDefaultLocation moves every floating date and time of the book into loc.
+func (b *Book) setDefaultLocation(loc *time.Location) {
+	b.DatePublishedStart.setDefaultLocation(loc)
+	b.DatePublishedEnd.setDefaultLocation(loc)
+
+	for _, chapter := range allChapters(&b.Chapters) {
+		chapter.DatePublished.setDefaultLocation(loc)
+		chapter.DateUpdated.setDefaultLocation(loc)
+	}
+}
+
+// Slug returns the unique ID of the book as a slug (e.g. "my-book" for "My Book").
+func (b Book) Slug() string {
+	return slugify(b.UniqueID)
+}
+
 func (b *Book) SetUniqueID(uniqueID string) {
 	b.UniqueID = strings.ToLower(strings.TrimSpace(uniqueID))
 }
@@ -137,65 +210,280 @@ func (b Book) ChaptersAndSubchapters() []*Chapter {
 	return allChapters(&b.Chapters)
 }
 
+// EnsureValid checks the book and its chapters, returning [Diagnostics] if any
+// problem is an error. Warnings are available from [Book.Diagnostics].
 func (b *Book) EnsureValid() error {
-	// trim unnecessary characters from unique ID
+	v := validator{sources: b.sources}
+	b.validate(&v)
+	b.diagnostics = v.diagnostics
+
+	return v.err()
+}
+
+// ProfileByID returns the registry profile with the given ID, or nil.
+func (b Book) ProfileByID(id string) *Profile {
+	profile, ok := b.ProfilesRegistry[id]
+	if !ok {
+		return nil
+	}
+
+	return &profile
+}
+
+// AssetByName returns the asset with the given name (e.g. "images/cover"), or nil.
+func (b Book) AssetByName(name string) *Asset {
+	for i := range b.Assets {
+		if b.Assets[i].Name == name {
+			return &b.Assets[i]
+		}
+	}
+
+	return nil
+}
+
+// Diagnostics returns the problems found by the last call to [Book.EnsureValid],
+// and those of the book's translations.
+func (b Book) Diagnostics() Diagnostics {
+	diagnostics := slices.Clip(b.diagnostics)
+	for _, translation := range b.Translations {
+		for _, d := range translation.Diagnostics() {
+			if !slices.ContainsFunc(diagnostics, func(e Diagnostic) bool { return e.Error() == d.Error() }) {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// normalize trims and fills in the fields of the book and its chapters before
+// they are validated.
+func (b *Book) normalize() {
 	b.SetUniqueID(b.UniqueID)
 
 	// use uniqueID as a title if there are no titles
-	if b.Title == "" && b.UniqueID != "" {
+	if b.Title == "" {
 		b.Title = b.UniqueID
 	}
 
+	// apply the default time zone to dates and times written without one
+	if b.TimeZone != "" {
+		if tz, err := time.LoadLocation(b.TimeZone); err == nil {
+			b.setDefaultLocation(tz)
+		}
+	}
+
+	for id, profile := range b.ProfilesRegistry {
+		if profile.ID == "" {
+			profile.ID = id
+			b.ProfilesRegistry[id] = profile
+		}
+	}
+	b.resolveProfiles()
+
+	b.IDs.normalize()
+
+	for i := range b.Copyright.Licenses {
+		b.Copyright.Licenses[i].normalize()
+	}
+
+	b.Advisories.normalize()
+	normalizeAdvisoryValues(b.ContentWarnings, &b.Rating)
+
+	for i := range b.Taxonomies {
+		b.Taxonomies[i].loc = b.loc.index("taxonomies", i)
+		b.Taxonomies[i].normalize()
+	}
+
+	for i := range b.ReadingOrders {
+		if b.ReadingOrders[i].loc == nil {
+			b.ReadingOrders[i].loc = b.loc.index("reading_orders", i)
+		}
+		b.ReadingOrders[i].normalize()
+	}
+
+	for i := range b.Chapters {
+		b.Chapters[i].normalize()
+	}
+}
+
+func (b *Book) validate(v *validator) {
+	loc := b.loc
+
+	v.checkUnknownKeys(b.options.strict)
+
+	for _, d := range b.deferred {
+		v.error(d.loc, d.err)
+	}
+
+	if b.sourceSchema < CurrentSchemaVersion {
+		v.warning(loc.field(SchemaVersionKey), ErrSchemaVersionOutdated{Version: b.sourceSchema})
+	}
+
 	if b.UniqueID == "" {
-		return ErrBookMissingUniqueID
+		v.error(loc.field("unique_id"), ErrBookMissingUniqueID)
 	}
 
 	if b.Title == "" {
-		return ErrBookMissingTitle
+		v.error(loc.field("title"), ErrBookMissingTitle)
 	}
 
 	if b.LanguageCode == "" {
-		return ErrBookMissingLanguageCode
+		v.error(loc.field("language_code"), ErrBookMissingLanguageCode)
 	}
 
-	var profiles []Profile
-	profiles = append(profiles, b.Authors...)
-	profiles = append(profiles, b.Publishers...)
-	profiles = append(profiles, b.Contributors...)
-	for _, profile := range profiles {
-		if err := profile.EnsureValid(); err != nil {
-			return err
+	if b.TimeZone != "" {
+		_, err := time.LoadLocation(b.TimeZone)
+		v.check(loc.field("time_zone"), err)
+	}
+
+	for i, tag := range b.Tags {
+		if strings.TrimSpace(tag) == "" {
+			v.error(loc.index("tags", i), ErrBookEmptyTag{Index: i + 1, Input: ""})
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(b.ProfilesRegistry)) {
+		profile := b.ProfilesRegistry[id]
+		profile.validate(v, append(loc.field("profiles_registry").field(id), at(b.profilesPath, "$."+id)...), b.ProfilesRegistry)
+	}
+
+	for i := range b.Authors {
+		b.Authors[i].validate(v, loc.index("authors", i), b.ProfilesRegistry)
+	}
+
+	for i := range b.Publishers {
+		b.Publishers[i].validate(v, loc.index("publishers", i), b.ProfilesRegistry)
+	}
+
+	for i := range b.Contributors {
+		b.Contributors[i].validate(v, loc.index("contributors", i), b.ProfilesRegistry)
+	}
+
+	for i := range b.Series {
+		b.Series[i].validate(v, loc.index("series", i))
+
+		// series are referenced by ID from the series.yml of a library
+		if id := b.Series[i].ID; id != "" && !b.options.inLibrary {
+			v.warning(loc.index("series", i).field("id"), ErrSeriesOutsideLibrary{ID: id})
 		}
 	}
 
-	for _, series := range b.Series {
-		if err := series.EnsureValid(); err != nil {
-			return err
+	for i := range b.LinksFunding {
+		b.LinksFunding[i].validate(v, loc.index("links_funding", i), "")
+	}
+
+	for i := range b.LinksMirrors {
+		b.LinksMirrors[i].validate(v, loc.index("links_mirrors", i), "")
+	}
+
+	for i := range b.LinksOther {
+		b.LinksOther[i].validate(v, loc.index("links_other", i), "")
+	}
+
+	b.IDs.validate(v, loc.field("ids"))
+
+	for i := range b.Copyright.Licenses {
+		b.Copyright.Licenses[i].validate(v, loc.field("copyright").index("licenses", i))
+	}
+
+	for i := range b.Assets {
+		asset := &b.Assets[i]
+		assetLoc := asset.loc
+		if assetLoc == nil {
+			assetLoc = loc.index("assets", i)
 		}
+
+		asset.validate(v, assetLoc)
 	}
 
-	var references []Reference
-	references = append(references, b.LinksFunding...)
-	references = append(references, b.LinksMirrors...)
-	references = append(references, b.LinksOther...)
+	taxonomies := make(map[string]bool)
+	taxonomySlugs := make(map[string]string)
+	for i := range b.Taxonomies {
+		taxonomy := &b.Taxonomies[i]
+		taxonomy.validate(v)
+		if taxonomy.ID == "" {
+			continue
+		}
 
-	for _, reference := range references {
-		if err := reference.EnsureValid(); err != nil {
-			return err
+		// each taxonomy is written to a directory named after its slug
+		other, seen := taxonomySlugs[taxonomy.Slug]
+		switch {
+		case taxonomies[taxonomy.ID]:
+			v.error(taxonomy.loc.field("id"), ErrTaxonomyDuplicateID{ID: taxonomy.ID})
+		case !isSafeSlug(taxonomy.Slug):
+			v.error(taxonomy.loc.field("id"), ErrTaxonomySlugUnsafe{ID: taxonomy.ID})
+		case seen:
+			v.error(taxonomy.loc.field("id"), ErrTaxonomyDuplicateSlug{ID: taxonomy.ID, Other: other, Slug: taxonomy.Slug})
 		}
+		taxonomies[taxonomy.ID] = true
+		taxonomySlugs[taxonomy.Slug] = taxonomy.ID
 	}
 
-	for _, asset := range b.Assets {
-		if err := asset.EnsureValid(); err != nil {
-			return err
+	b.Advisories.validate(v, loc.field("advisories"))
+	b.Advisories.validateValues(v, loc, b.ContentWarnings, b.Rating)
+
+	for i := range b.Chapters {
+		b.Chapters[i].validate(v)
+	}
+
+	seen := make(map[string]bool)
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		if chapter.UniqueID == "" {
+			continue
+		}
+
+		if seen[chapter.UniqueID] {
+			v.error(chapter.loc.field("unique_id"), ErrChapterDuplicateUniqueID{UniqueID: chapter.UniqueID})
 		}
+		seen[chapter.UniqueID] = true
 	}
 
-	for _, chapter := range b.Chapters {
-		if err := chapter.EnsureValid(); err != nil {
-			return err
+	// each split volume is written to a directory named after its slug
+	if b.SplitVolumes {
+		volumeSlugs := make(map[string]string)
+		for i := range b.Chapters {
+			volume := &b.Chapters[i]
+			if !volume.IsVolume() || volume.UniqueID == "" {
+				continue
+			}
+
+			other, seen := volumeSlugs[volume.Slug()]
+			switch {
+			case !isSafeSlug(volume.Slug()):
+				v.error(volume.loc.field("unique_id"), ErrChapterVolumeSlugUnsafe{UniqueID: volume.UniqueID})
+			case seen && other != volume.UniqueID:
+				v.error(volume.loc.field("unique_id"), ErrChapterVolumeDuplicateSlug{UniqueID: volume.UniqueID, Other: other, Slug: volume.Slug()})
+			}
+			volumeSlugs[volume.Slug()] = volume.UniqueID
 		}
 	}
 
-	return nil
+	chapters := make(map[string]*Chapter)
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		chapters[chapter.UniqueID] = chapter
+	}
+
+	orders := make(map[string]bool)
+	slugs := make(map[string]string)
+	for i := range b.ReadingOrders {
+		order := &b.ReadingOrders[i]
+		order.validate(v, chapters)
+		if order.ID == "" {
+			continue
+		}
+
+		// each reading order is written to a page named after its slug
+		other, seen := slugs[order.Slug]
+		switch {
+		case orders[order.ID]:
+			v.error(order.loc.field("id"), ErrReadingOrderDuplicateID{ID: order.ID})
+		case !isSafeSlug(order.Slug):
+			v.error(order.loc.field("id"), ErrReadingOrderSlugUnsafe{ID: order.ID})
+		case seen:
+			v.error(order.loc.field("id"), ErrReadingOrderDuplicateSlug{ID: order.ID, Other: other, Slug: order.Slug})
+		}
+		orders[order.ID] = true
+		slugs[order.Slug] = order.ID
+	}
 }
@@ -2,6 +2,7 @@ package pub
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 )
@@ -9,33 +10,85 @@ import (
 var (
 	ErrChapterMissingBookPointer = errors.New("missing pointer to Book")
 	ErrChapterMissingUniqueID    = errors.New("missing UniqueID (must have at least 1 non-space character)")
+
+	ErrChapterFrontMatterHasChapters        = errors.New("front matter cannot define chapters (nested chapters must be defined in nav.yml)")
+	ErrChapterFrontMatterHasContentFileName = errors.New("front matter cannot define content_file_name")
 )
 
+type ErrChapterDuplicateUniqueID struct {
+	UniqueID string
+}
+
+func (e ErrChapterDuplicateUniqueID) Error() string {
+	return fmt.Sprintf("chapter UniqueID \"%s\" is used by more than one chapter (each chapter must have a unique UniqueID)", e.UniqueID)
+}
+
+type ErrChapterEmpty struct {
+	UniqueID string
+}
+
+func (e ErrChapterEmpty) Error() string {
+	return fmt.Sprintf("chapter \"%s\" has no content and no subchapters", e.UniqueID)
+}
+
+type ErrChapterGlobWithContent struct {
+	Glob string
+}
+
+func (e ErrChapterGlobWithContent) Error() string {
+	return fmt.Sprintf("chapter entry with glob \"%s\" cannot also define content_file_name or chapters", e.Glob)
+}
+
 // Chapter represents a division in a [Book] that contains its primary [Content].
+//
+// Fields may be defined in nav.yml and in the front matter of the content file,
+// which overrides nav.yml except for Extra, whose keys are merged.
+//
+// AuthorsNotePrefix and AuthorsNoteSuffix may also be written in sidecar files
+// (e.g. "chapter-1.note-before.md") or in sections of the content file.
+//
+// A nav.yml entry with a Glob (e.g. "part-2/*.md") becomes a chapter per matching
+// file. Without a nav.yml, chapters are discovered from the chapters directory.
 type Chapter struct {
-	UniqueID          string            `json:"unique_id"`
-	Title             string            `json:"title"`
-	Subtitle          string            `json:"subtitle"`
-	Authors           []Profile         `json:"authors"`
-	Contributors      []Profile         `json:"contributors"`
-	Publishers        []Profile         `json:"publishers"`
-	ContentFileName   string            `json:"content_file_name"`
-	Content           Content           `json:"content"`
-	AuthorsNotePrefix Content           `json:"authors_note_prefix"`
-	AuthorsNoteSuffix Content           `json:"authors_note_suffix"`
-	URL               string            `json:"url"`
-	LanguageCode      string            `json:"language_code"`
-	DatePublished     *DateTime         `json:"date_published"`
-	DateUpdated       *DateTime         `json:"date_updated"`
-	IDs               map[string]string `json:"ids"`
-	Copyright         Copyright         `json:"copyright"`
-	Extra             map[string]any    `json:"extra"`
-	Chapters          []Chapter         `json:"chapters"`
-
-	Previous  *Chapter
-	Next      *Chapter
-	Book      *Book
-	InputPath string
+	UniqueID          string              `json:"unique_id"`
+	Title             string              `json:"title"`
+	Subtitle          string              `json:"subtitle"`
+	Authors           []Profile           `json:"authors"`
+	Contributors      []Profile           `json:"contributors"`
+	Publishers        []Profile           `json:"publishers"`
+	ContentFileName   string              `json:"content_file_name"`
+	Content           Content             `json:"content"`
+	AuthorsNotePrefix Content             `json:"authors_note_prefix"`
+	AuthorsNoteSuffix Content             `json:"authors_note_suffix"`
+	URL               string              `json:"url"`
+	LanguageCode      string              `json:"language_code"`
+	DatePublished     *DateTime           `json:"date_published"`
+	DateUpdated       *DateTime           `json:"date_updated"`
+	IDs               Identifiers         `json:"ids"`
+	Copyright         Copyright           `json:"copyright"`
+	ContentWarnings   []string            `json:"content_warnings"`
+	Rating            string              `json:"rating"`
+	Taxonomies        map[string][]string `json:"taxonomies"`
+	Extra             map[string]any      `json:"extra"`
+	Chapters          []Chapter           `json:"chapters"`
+	Glob              string              `json:"glob"`
+	State             ChapterState        `json:"state"`
+	Kind              ChapterKind         `json:"kind"`
+
+	// Untranslated is set on the chapters of a translation with the original's content.
+	Untranslated bool `json:"untranslated"`
+
+	Previous   *Chapter `json:"-"`
+	Next       *Chapter `json:"-"`
+	Parent     *Chapter `json:"-"`
+	Depth      int      `json:"-"`
+	Index      int      `json:"-"`
+	NumberPath []int    `json:"-"`
+	Book       *Book    `json:"-"`
+	InputPath  string   `json:"-"`
+
+	loc           location
+	readingOrders map[string]chapterLinks
 }
 
 func (c *Chapter) SetBook(book *Book) error {
@@ -76,7 +129,7 @@ func (c *Chapter) SetDatePublishedFromString(input string) error {
 	if err != nil {
 		return err
 	}
-	*c.DatePublished = t
+	c.DatePublished = &t
 
 	return nil
 }
@@ -86,11 +139,83 @@ func (c *Chapter) SetDateUpdatedFromString(input string) error {
 	if err != nil {
 		return err
 	}
-	*c.DateUpdated = t
+	c.DateUpdated = &t
 
 	return nil
 }
 
+// Ancestors returns the chapters containing this chapter, top-level chapter first.
+func (c Chapter) Ancestors() []*Chapter {
+	var ancestors []*Chapter
+	for parent := c.Parent; parent != nil; parent = parent.Parent {
+		ancestors = append([]*Chapter{parent}, ancestors...)
+	}
+
+	return ancestors
+}
+
+// Number returns the chapter's hierarchical number (e.g. "1.2.1" or "A.1").
+func (c Chapter) Number() string {
+	return c.numbering().format(c.NumberPath, c.numberStyles(), c.languageCode())
+}
+
+// LocalNumber returns the chapter's number among its siblings (e.g. "1" of "1.2.1").
+func (c Chapter) LocalNumber() string {
+	if len(c.NumberPath) == 0 {
+		return ""
+	}
+
+	styles := c.numberStyles()
+	return styles[len(styles)-1].Format(c.NumberPath[len(c.NumberPath)-1], c.languageCode())
+}
+
+// numberStyles returns the numbering style of each level of the chapter's number
+// path, using the appendix style for appendices.
+func (c Chapter) numberStyles() []NumberingStyle {
+	numbering := c.numbering()
+	levels := append(c.Ancestors(), &c)
+
+	styles := make([]NumberingStyle, len(c.NumberPath))
+	for depth := range styles {
+		styles[depth] = numbering.Style(depth)
+		if depth < len(levels) && levels[depth].Kind == ChapterKindAppendix {
+			styles[depth] = numbering.AppendixStyle()
+		}
+	}
+
+	return styles
+}
+
+// NumberAs returns the chapter's number among its siblings in the given style.
+func (c Chapter) NumberAs(style string) (string, error) {
+	var s NumberingStyle
+	if err := s.UnmarshalText([]byte(style)); err != nil {
+		return "", err
+	}
+
+	if len(c.NumberPath) == 0 {
+		return "", nil
+	}
+
+	return s.Format(c.NumberPath[len(c.NumberPath)-1], c.languageCode()), nil
+}
+
+func (c Chapter) numbering() Numbering {
+	if c.Book == nil {
+		return Numbering{}
+	}
+
+	return c.Book.Numbering
+}
+
+func (c Chapter) languageCode() string {
+	if c.LanguageCode == "" && c.Book != nil {
+		return c.Book.LanguageCode
+	}
+
+	return c.LanguageCode
+}
+
 func (c Chapter) HasSubchapters() bool {
 	return len(c.Chapters) > 0
 }
@@ -99,16 +224,21 @@ func (c Chapter) Subchapters() []*Chapter {
 	return allChapters(&c.Chapters)
 }
 
+// EnsureValid checks the chapter and its subchapters, returning [Diagnostics] if
+// any problem is an error.
 func (c *Chapter) EnsureValid() error {
-	if c.Book == nil {
-		return ErrChapterMissingBookPointer
+	v := validator{}
+	if c.Book != nil {
+		v.sources = c.Book.sources
 	}
+	c.validate(&v)
 
-	c.SetUniqueID(c.UniqueID)
-	if c.UniqueID == "" && c.Title == "" && c.ContentFileName == "" {
-		return ErrChapterMissingUniqueID
-	}
+	return v.err()
+}
 
+// normalize trims and fills in the fields of the chapter and its subchapters.
+func (c *Chapter) normalize() {
+	c.SetUniqueID(c.UniqueID)
 	if c.UniqueID == "" && c.Title != "" {
 		c.SetUniqueID(c.Title)
 	}
@@ -124,21 +254,72 @@ func (c *Chapter) EnsureValid() error {
 		c.Title = c.UniqueID
 	}
 
-	var profiles []Profile
-	profiles = append(profiles, c.Authors...)
-	profiles = append(profiles, c.Publishers...)
-	profiles = append(profiles, c.Contributors...)
-	for _, profile := range profiles {
-		if err := profile.EnsureValid(); err != nil {
-			return err
-		}
+	c.IDs.normalize()
+
+	for i := range c.Copyright.Licenses {
+		c.Copyright.Licenses[i].normalize()
 	}
 
-	for _, subchapter := range c.Chapters {
-		if err := subchapter.EnsureValid(); err != nil {
-			return err
+	normalizeAdvisoryValues(c.ContentWarnings, &c.Rating)
+	c.normalizeTaxonomies()
+
+	for i := range c.Chapters {
+		subchapter := &c.Chapters[i]
+		if subchapter.loc == nil {
+			subchapter.loc = c.loc.index("chapters", i)
 		}
+
+		subchapter.normalize()
 	}
+}
 
-	return nil
+func (c *Chapter) validate(v *validator) {
+	loc := c.loc
+
+	var registry map[string]Profile
+	if c.Book != nil {
+		registry = c.Book.ProfilesRegistry
+	}
+
+	if c.Book == nil {
+		v.error(loc, ErrChapterMissingBookPointer)
+	}
+
+	if c.UniqueID == "" {
+		v.error(loc.field("unique_id"), ErrChapterMissingUniqueID)
+	}
+
+	if c.Content.Len() == 0 && len(c.Chapters) == 0 && !c.IsPlaceholder() {
+		v.warning(loc, ErrChapterEmpty{UniqueID: c.UniqueID})
+	}
+
+	for i := range c.Authors {
+		c.Authors[i].validate(v, loc.index("authors", i), registry)
+	}
+
+	for i := range c.Publishers {
+		c.Publishers[i].validate(v, loc.index("publishers", i), registry)
+	}
+
+	for i := range c.Contributors {
+		c.Contributors[i].validate(v, loc.index("contributors", i), registry)
+	}
+
+	c.IDs.validate(v, loc.field("ids"))
+
+	c.advisories().validateValues(v, loc, c.ContentWarnings, c.Rating)
+	c.validateTaxonomies(v, loc.field("taxonomies"))
+
+	for i := range c.Copyright.Licenses {
+		c.Copyright.Licenses[i].validate(v, loc.field("copyright").index("licenses", i))
+	}
+
+	for i := range c.Chapters {
+		subchapter := &c.Chapters[i]
+		subchapter.validate(v)
+
+		if subchapter.IsVolume() {
+			v.warning(subchapter.loc.field("kind"), ErrChapterVolumeNotTopLevel{UniqueID: subchapter.UniqueID})
+		}
+	}
 }
@@ -0,0 +1,182 @@
+package pub
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ChapterKind is the structural role of a [Chapter] in its book.
+type ChapterKind int
+
+const (
+	// ChapterKindChapter chapters are the numbered chapters of the body of the book.
+	ChapterKindChapter ChapterKind = iota
+	// ChapterKindPart chapters group their chapters (e.g. "Part One") on a divider
+	// page, and take up a number among their siblings.
+	ChapterKindPart
+	// ChapterKindVolume chapters are top-level parts, split into sub-books with SplitVolumes.
+	ChapterKindVolume
+	// ChapterKindInterlude chapters are unnumbered chapters in the body of the book.
+	ChapterKindInterlude
+	// ChapterKindAppendix chapters are placed after the body, and numbered separately.
+	ChapterKindAppendix
+	// ChapterKindFrontMatter chapters (e.g. a preface) are unnumbered, before the body.
+	ChapterKindFrontMatter
+	// ChapterKindBackMatter chapters (e.g. a glossary) are unnumbered, after appendices.
+	ChapterKindBackMatter
+)
+
+var (
+	ChapterKindMap = map[string]ChapterKind{
+		"chapter":      ChapterKindChapter,
+		"part":         ChapterKindPart,
+		"volume":       ChapterKindVolume,
+		"interlude":    ChapterKindInterlude,
+		"appendix":     ChapterKindAppendix,
+		"front-matter": ChapterKindFrontMatter,
+		"back-matter":  ChapterKindBackMatter,
+	}
+)
+
+type ErrChapterKindMarshalUnrecognized struct {
+	Value ChapterKind
+}
+
+func (e ErrChapterKindMarshalUnrecognized) Error() string {
+	return fmt.Sprintf("kind: unrecognized value %d", int(e.Value))
+}
+
+type ErrChapterKindUnmarshalUnrecognized struct {
+	KindString string
+}
+
+func (e ErrChapterKindUnmarshalUnrecognized) Error() string {
+	return fmt.Sprintf("kind: unrecognized value \"%s\" (value must be one of the following (case doesn't matter): %v)", e.KindString, strings.Join(slices.Sorted(maps.Keys(ChapterKindMap)), ", "))
+}
+
+type ErrChapterVolumeNotTopLevel struct {
+	UniqueID string
+}
+
+func (e ErrChapterVolumeNotTopLevel) Error() string {
+	return fmt.Sprintf("chapter \"%s\" is a volume, but is nested in another chapter (volumes must be top-level chapters to be split into sub-books)", e.UniqueID)
+}
+
+type ErrChapterVolumeSlugUnsafe struct {
+	UniqueID string
+}
+
+func (e ErrChapterVolumeSlugUnsafe) Error() string {
+	return fmt.Sprintf("chapter \"%s\" is a volume whose unique ID cannot name its sub-book's directory (it must contain a letter or digit and must not start with \".\")", e.UniqueID)
+}
+
+type ErrChapterVolumeDuplicateSlug struct {
+	UniqueID string
+	Other    string
+	Slug     string
+}
+
+func (e ErrChapterVolumeDuplicateSlug) Error() string {
+	return fmt.Sprintf("volumes \"%s\" and \"%s\" would both be written to the directory \"%s\" (give them unique IDs that differ in more than case and punctuation)", e.Other, e.UniqueID, e.Slug)
+}
+
+func (k *ChapterKind) UnmarshalText(text []byte) error {
+	vStr := string(text)
+
+	v, ok := ChapterKindMap[strings.ToLower(strings.TrimSpace(vStr))]
+	if !ok {
+		return ErrChapterKindUnmarshalUnrecognized{KindString: vStr}
+	}
+
+	*k = v
+
+	return nil
+}
+
+func (k ChapterKind) MarshalText() ([]byte, error) {
+	for key, v := range ChapterKindMap {
+		if v == k {
+			return []byte(key), nil
+		}
+	}
+
+	return nil, ErrChapterKindMarshalUnrecognized{Value: k}
+}
+
+func (k ChapterKind) String() string {
+	b, err := k.MarshalText()
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// IsPart reports whether the chapter is a part.
+func (c Chapter) IsPart() bool {
+	return c.Kind == ChapterKindPart
+}
+
+// IsVolume reports whether the chapter is a volume.
+func (c Chapter) IsVolume() bool {
+	return c.Kind == ChapterKindVolume
+}
+
+// IsDivision reports whether the chapter is a part or a volume.
+func (c Chapter) IsDivision() bool {
+	return c.IsPart() || c.IsVolume()
+}
+
+// IsSplitVolume reports whether the chapter is rendered as a sub-book of its own.
+func (c Chapter) IsSplitVolume() bool {
+	return c.IsVolume() && c.Parent == nil && c.Book != nil && c.Book.SplitVolumes
+}
+
+// Slug returns the unique ID of the chapter as a slug.
+func (c Chapter) Slug() string {
+	return slugify(c.UniqueID)
+}
+
+// InSplitVolume reports whether the chapter is, or is nested in, a split volume.
+func (c Chapter) InSplitVolume() bool {
+	if c.IsSplitVolume() {
+		return true
+	}
+
+	ancestors := c.Ancestors()
+
+	return len(ancestors) > 0 && ancestors[0].IsSplitVolume()
+}
+
+// isNumbered reports whether the chapter's kind is counted by chapter numbers.
+func (k ChapterKind) isNumbered() bool {
+	return k != ChapterKindInterlude && k != ChapterKindFrontMatter && k != ChapterKindBackMatter
+}
+
+// order returns the position of chapters of kind k among their siblings.
+func (k ChapterKind) order() int {
+	switch k {
+	case ChapterKindFrontMatter:
+		return 0
+	case ChapterKindAppendix:
+		return 2
+	case ChapterKindBackMatter:
+		return 3
+	}
+
+	return 1
+}
+
+// orderChapters sorts chapters and their subchapters by [ChapterKind.order], stably.
+func orderChapters(chapters []Chapter) {
+	slices.SortStableFunc(chapters, func(a, b Chapter) int {
+		return cmp.Compare(a.Kind.order(), b.Kind.order())
+	})
+
+	for i := range chapters {
+		orderChapters(chapters[i].Chapters)
+	}
+}
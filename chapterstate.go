@@ -0,0 +1,119 @@
+package pub
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChapterState is the publication state of a [Chapter]. The zero value is [ChapterPublished].
+type ChapterState int
+
+const (
+	// ChapterPublished chapters are built, unless [Chapter.IsScheduled].
+	ChapterPublished ChapterState = iota
+	// ChapterDraft chapters are left out of the book, unless it is loaded with [WithDrafts].
+	ChapterDraft
+	// ChapterPlaceholder chapters (e.g. "Coming soon") are listed, but have no page.
+	ChapterPlaceholder
+)
+
+var (
+	ChapterStateMap = map[string]ChapterState{
+		"published":   ChapterPublished,
+		"draft":       ChapterDraft,
+		"placeholder": ChapterPlaceholder,
+	}
+)
+
+type ErrChapterStateMarshalUnrecognized struct {
+	Value ChapterState
+}
+
+func (e ErrChapterStateMarshalUnrecognized) Error() string {
+	return fmt.Sprintf("state: unrecognized value %d", int(e.Value))
+}
+
+type ErrChapterStateUnmarshalUnrecognized struct {
+	StateString string
+}
+
+func (e ErrChapterStateUnmarshalUnrecognized) Error() string {
+	return fmt.Sprintf("state: unrecognized value \"%s\" (value must be one of the following (case doesn't matter): %v)", e.StateString, strings.Join(slices.Sorted(maps.Keys(ChapterStateMap)), ", "))
+}
+
+func (s *ChapterState) UnmarshalText(text []byte) error {
+	vStr := string(text)
+
+	v, ok := ChapterStateMap[strings.ToLower(strings.TrimSpace(vStr))]
+	if !ok {
+		return ErrChapterStateUnmarshalUnrecognized{StateString: vStr}
+	}
+
+	*s = v
+
+	return nil
+}
+
+func (s ChapterState) MarshalText() ([]byte, error) {
+	for k, v := range ChapterStateMap {
+		if v == s {
+			return []byte(k), nil
+		}
+	}
+
+	return nil, ErrChapterStateMarshalUnrecognized{Value: s}
+}
+
+func (s ChapterState) String() string {
+	b, err := s.MarshalText()
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// IsDraft reports whether the chapter is a draft.
+func (c Chapter) IsDraft() bool {
+	return c.State == ChapterDraft
+}
+
+// IsPlaceholder reports whether the chapter is a placeholder.
+func (c Chapter) IsPlaceholder() bool {
+	return c.State == ChapterPlaceholder
+}
+
+// IsScheduled reports whether the chapter's DatePublished is after the book's
+// build time. See [WithBuildTime].
+func (c Chapter) IsScheduled() bool {
+	if c.DatePublished == nil {
+		return false
+	}
+
+	now := time.Now()
+	if c.Book != nil && !c.Book.options.buildTime.IsZero() {
+		now = c.Book.options.buildTime
+	}
+
+	return c.DatePublished.After(now)
+}
+
+// publishedChapters returns the chapters without the drafts and scheduled
+// chapters, unless the book's options include them.
+func (b *Book) publishedChapters(chapters []Chapter) []Chapter {
+	var published []Chapter
+	for _, chapter := range chapters {
+		if !b.options.drafts && (chapter.IsDraft() || (chapter.IsScheduled() && !chapter.IsPlaceholder())) {
+			b.Withheld = append(b.Withheld, chapter.UniqueID)
+			continue
+		}
+
+		chapter.Chapters = b.publishedChapters(chapter.Chapters)
+		published = append(published, chapter)
+	}
+
+	return published
+}
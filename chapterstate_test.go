@@ -0,0 +1,117 @@
+package pub
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestChapterStateUnmarshalText(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ChapterState
+		wantErr bool
+	}{
+		{"draft", ChapterDraft, false},
+		{" Placeholder ", ChapterPlaceholder, false},
+		{"PUBLISHED", ChapterPublished, false},
+		{"scheduled", ChapterPublished, true},
+	}
+
+	for _, tt := range tests {
+		var got ChapterState
+		err := got.UnmarshalText([]byte(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalText(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPublishedChapters(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+		"book/nav.yml": {Data: []byte(`- content_file_name: one.md
+- content_file_name: draft.md
+  state: draft
+  chapters:
+    - content_file_name: draft-sub.md
+- content_file_name: placeholder.md
+  state: placeholder
+  date_published: 2025-10-01
+- content_file_name: scheduled.md
+  date_published: 2025-10-01
+- content_file_name: two.md
+`)},
+		"book/chapters/one.md":         {Data: []byte("One\n")},
+		"book/chapters/draft.md":       {Data: []byte("Draft\n")},
+		"book/chapters/draft-sub.md":   {Data: []byte("Draft subchapter\n")},
+		"book/chapters/placeholder.md": {Data: []byte("")},
+		"book/chapters/scheduled.md":   {Data: []byte("Scheduled\n")},
+		"book/chapters/two.md":         {Data: []byte("Two\n")},
+	}
+
+	buildTime := WithBuildTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name         string
+		options      []BookOption
+		wantChapters []string
+		wantWithheld []string
+		wantNext     []string
+	}{
+		{
+			"before date",
+			[]BookOption{buildTime},
+			[]string{"one", "placeholder", "two"},
+			[]string{"draft", "scheduled"},
+			[]string{"two", "", ""},
+		},
+		{
+			"after date",
+			[]BookOption{WithBuildTime(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC))},
+			[]string{"one", "placeholder", "scheduled", "two"},
+			[]string{"draft"},
+			[]string{"scheduled", "", "two", ""},
+		},
+		{
+			"drafts",
+			[]BookOption{buildTime, WithDrafts()},
+			[]string{"one", "draft", "draft-sub", "placeholder", "scheduled", "two"},
+			nil,
+			[]string{"draft", "draft-sub", "scheduled", "", "two", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book, err := NewBookFS(fsys, "book", tt.options...)
+			if err != nil {
+				t.Fatalf("NewBookFS() error = %v", err)
+			}
+
+			var chapters, next []string
+			for _, chapter := range book.ChaptersAndSubchapters() {
+				chapters = append(chapters, chapter.UniqueID)
+				if chapter.Next != nil {
+					next = append(next, chapter.Next.UniqueID)
+				} else {
+					next = append(next, "")
+				}
+			}
+
+			if !reflect.DeepEqual(chapters, tt.wantChapters) {
+				t.Errorf("chapters = %v, want %v", chapters, tt.wantChapters)
+			}
+			if !reflect.DeepEqual(book.Withheld, tt.wantWithheld) {
+				t.Errorf("Withheld = %v, want %v", book.Withheld, tt.wantWithheld)
+			}
+			if !reflect.DeepEqual(next, tt.wantNext) {
+				t.Errorf("Next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
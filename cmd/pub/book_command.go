@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -92,7 +93,7 @@ func (b BookInitCommand) Run(ctx *Context) error {
 	defer fPub.Close()
 
 	// TODO: probably interactively prompt user on certain required fields
-	if _, err := fPub.Write([]byte(`unique_id: "` + strings.ToLower(filepath.Base(absPath)) + `"`)); err != nil {
+	if _, err := fPub.Write([]byte(fmt.Sprintf("%s: %d\nunique_id: \"%s\"", pub.SchemaVersionKey, pub.CurrentSchemaVersion, strings.ToLower(filepath.Base(absPath))))); err != nil {
 		return err
 	}
 
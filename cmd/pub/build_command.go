@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/JessebotX/pub"
 	pubhtml "github.com/JessebotX/pub/renderer/html"
@@ -18,6 +20,11 @@ type BuildCommand struct {
 	OutputDirectory  *string `name:"output-directory" short:"o" help:"Directory for distributable output formats. By default, directory is relative to the specified input directory"`
 	LayoutsDirectory *string `name:"layouts-directory" short:"t" help:"Directory containing formatting instructions for distributable output formats. By default: directory is relative to the specified input directory"`
 	Minify           bool    `name:"minify" help:"Optimize file sizes of distributable output formats"`
+	Strict           bool    `name:"strict" xor:"save-uuid" help:"Treat unknown configuration keys as errors instead of warnings"`
+	Drafts           bool    `name:"drafts" help:"Include draft chapters and chapters scheduled to be published in the future"`
+	LazyContent      bool    `name:"lazy-content" help:"Read chapter content from disk only when it is rendered, keeping memory use low for books with many chapters"`
+	Profile          string  `name:"profile" short:"p" env:"PUB_PROFILE" help:"Name of the build profile (under \"build_profiles\" in pub.yml) to merge over the rest of the book's configuration"`
+	SaveUUID         bool    `name:"save-uuid" xor:"save-uuid" help:"Save the UUID generated for a book without one to its pub.yml, so that later builds use the same UUID"`
 }
 
 func (b BuildCommand) Run(ctx *Context) error {
@@ -49,19 +56,75 @@ func (b BuildCommand) Run(ctx *Context) error {
 		fmt.Println("CREATING NEW BOOK...")
 	}
 
-	book, err := pub.NewBook(inputDir)
+	var opts []pub.BookOption
+	if b.Strict {
+		opts = append(opts, pub.WithStrict())
+	}
+
+	if b.LazyContent {
+		opts = append(opts, pub.WithLazyContent())
+	}
+
+	if b.Drafts {
+		opts = append(opts, pub.WithDrafts())
+	}
+
+	if b.Profile != "" {
+		opts = append(opts, pub.WithProfile(b.Profile))
+	}
+
+	book, err := pub.NewBook(inputDir, opts...)
 	if err != nil {
 		return err
 	}
 
+	for _, warning := range book.Diagnostics().Warnings() {
+		fmt.Fprintf(os.Stderr, "[WARNING] %s\n", warning)
+	}
+
+	if len(book.Withheld) > 0 && !ctx.NoNonEssentialMessages {
+		fmt.Printf("Withheld %d draft or scheduled chapters (build with --drafts to include them): %s\n", len(book.Withheld), strings.Join(book.Withheld, ", "))
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		for _, language := range book.Languages {
+			if language.Original {
+				continue
+			}
+
+			fmt.Printf("Translation %s: %d of %d chapters translated (%d%%)", language.Code, language.Translated, language.Total, language.Completeness())
+			if len(language.Missing) > 0 {
+				fmt.Printf(", untranslated: %s", strings.Join(language.Missing, ", "))
+			}
+			fmt.Println()
+		}
+	}
+
+	if book.HasGeneratedUUID() && b.SaveUUID {
+		if err := book.PersistGeneratedUUID(); err != nil {
+			return err
+		}
+
+		if !ctx.NoNonEssentialMessages {
+			fmt.Printf("Generated UUID %s and saved it to %s\n", book.UUID(), pub.BookConfigFileName)
+		}
+	} else if book.HasGeneratedUUID() && !ctx.NoNonEssentialMessages {
+		fmt.Printf("Generated UUID %s for this build only (build with --save-uuid to save it to %s)\n", book.UUID(), pub.BookConfigFileName)
+	}
+
 	if !ctx.NoNonEssentialMessages {
 		fmt.Println("Done CREATING NEW BOOK!")
 	}
 
 	if ctx.Debug {
+		model, err := pub.MarshalModel(&book, pub.ModelFormatYAML)
+		if err != nil {
+			return err
+		}
+
 		fmt.Println("[DEBUG] --- BEGIN BOOK STRUCTURE ---")
 
-		fmt.Printf("[DEBUG] %#v\n", book)
+		fmt.Print(string(model))
 
 		fmt.Println("[DEBUG] ---  END BOOK STRUCTURE  ---")
 	}
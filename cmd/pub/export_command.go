@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JessebotX/pub"
+	pubhtml "github.com/JessebotX/pub/renderer/html"
+)
+
+type ExportCommand struct {
+	Model ExportModelCommand `cmd:"" help:"Export the fully resolved book model for other tools"`
+}
+
+type ExportModelCommand struct {
+	InputDirectory string  `name:"input-directory" type:"existingdir" default:"./" arg:"" help:"Directory containing the book project to export"`
+	Format         string  `name:"format" short:"f" default:"json" enum:"json,yaml" help:"Format of the exported model (one of: ${enum})"`
+	Rendered       bool    `name:"rendered" help:"Include the content of the book and its chapters rendered to HTML"`
+	Output         *string `name:"output" short:"o" help:"File to write the model to. By default, the model is printed to standard output"`
+	Strict         bool    `name:"strict" help:"Treat unknown configuration keys as errors instead of warnings"`
+	Drafts         bool    `name:"drafts" help:"Include draft chapters and chapters scheduled to be published in the future"`
+	Profile        string  `name:"profile" short:"p" env:"PUB_PROFILE" help:"Name of the build profile (under \"build_profiles\" in pub.yml) to merge over the rest of the book's configuration"`
+}
+
+func (e ExportModelCommand) Run(ctx *Context) error {
+	var opts []pub.BookOption
+	if e.Strict {
+		opts = append(opts, pub.WithStrict())
+	}
+
+	if e.Drafts {
+		opts = append(opts, pub.WithDrafts())
+	}
+
+	if e.Profile != "" {
+		opts = append(opts, pub.WithProfile(e.Profile))
+	}
+
+	book, err := pub.NewBook(e.InputDirectory, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range book.Diagnostics().Warnings() {
+		fmt.Fprintf(os.Stderr, "[WARNING] %s\n", warning)
+	}
+
+	if e.Rendered {
+		if err := pubhtml.AddHTMLFormats(&book); err != nil {
+			return err
+		}
+	}
+
+	data, err := pub.MarshalModel(&book, e.Format)
+	if err != nil {
+		return err
+	}
+
+	if e.Output == nil {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(*e.Output, data, 0666); err != nil {
+		return err
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		fmt.Printf("Exported the model of %s to %s\n", book.InputPath, *e.Output)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JessebotX/pub"
+	pubhtml "github.com/JessebotX/pub/renderer/html"
+)
+
+type LibraryCommand struct {
+	Build LibraryBuildCommand `cmd:"" help:"Build every book of a library and the library's catalog pages"`
+}
+
+type LibraryBuildCommand struct {
+	InputDirectory   string  `name:"input-directory" type:"existingdir" default:"./" arg:"" help:"Directory containing library.yml"`
+	OutputDirectory  *string `name:"output-directory" short:"o" help:"Directory for the built library. By default, directory is relative to the specified input directory"`
+	LayoutsDirectory *string `name:"layouts-directory" short:"t" help:"Directory containing formatting instructions shared by every book and the library's pages (under \"_library\"). By default: directory is relative to the specified input directory"`
+	Strict           bool    `name:"strict" xor:"save-uuid" help:"Treat unknown configuration keys as errors instead of warnings"`
+	Drafts           bool    `name:"drafts" help:"Include draft chapters and chapters scheduled to be published in the future"`
+	LazyContent      bool    `name:"lazy-content" help:"Read chapter content from disk only when it is rendered, keeping memory use low for books with many chapters"`
+	Profile          string  `name:"profile" short:"p" env:"PUB_PROFILE" help:"Name of the build profile (under \"build_profiles\" in pub.yml) to merge over the configuration of every book"`
+	SaveUUID         bool    `name:"save-uuid" xor:"save-uuid" help:"Save the UUID generated for a book without one to its pub.yml, so that later builds use the same UUID"`
+}
+
+func (l LibraryBuildCommand) Run(ctx *Context) error {
+	inputDir := l.InputDirectory
+	var outputDir, layoutsDir string
+	if l.OutputDirectory != nil {
+		outputDir = *l.OutputDirectory
+	} else {
+		outputDir = filepath.Join(inputDir, OutputDirName)
+	}
+
+	if l.LayoutsDirectory != nil {
+		layoutsDir = *l.LayoutsDirectory
+	} else {
+		layoutsDir = filepath.Join(inputDir, LayoutsDirName)
+	}
+
+	if ctx.Debug {
+		fmt.Printf("[DEBUG] Input Directory:   %s\n", inputDir)
+		fmt.Printf("[DEBUG] Layouts Directory: %s\n", layoutsDir)
+		fmt.Printf("[DEBUG] Output Directory:  %s\n", outputDir)
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		fmt.Println("LOADING LIBRARY...")
+	}
+
+	var opts []pub.BookOption
+	if l.Strict {
+		opts = append(opts, pub.WithStrict())
+	}
+
+	if l.LazyContent {
+		opts = append(opts, pub.WithLazyContent())
+	}
+
+	if l.Drafts {
+		opts = append(opts, pub.WithDrafts())
+	}
+
+	if l.Profile != "" {
+		opts = append(opts, pub.WithProfile(l.Profile))
+	}
+
+	library, err := pub.NewLibrary(inputDir, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range library.Diagnostics().Warnings() {
+		fmt.Fprintf(os.Stderr, "[WARNING] %s\n", warning)
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		for _, book := range library.Books {
+			if len(book.Withheld) > 0 {
+				fmt.Printf("Withheld %d draft or scheduled chapters of %s (build with --drafts to include them): %s\n", len(book.Withheld), book.UniqueID, strings.Join(book.Withheld, ", "))
+			}
+		}
+	}
+
+	for _, book := range library.Books {
+		if !book.HasGeneratedUUID() {
+			continue
+		}
+
+		configPath := filepath.Join(book.InputPath, pub.BookConfigFileName)
+		if !l.SaveUUID {
+			if !ctx.NoNonEssentialMessages {
+				fmt.Printf("Generated UUID %s for this build only (build with --save-uuid to save it to %s)\n", book.UUID(), configPath)
+			}
+			continue
+		}
+
+		if err := book.PersistGeneratedUUID(); err != nil {
+			return err
+		}
+
+		if !ctx.NoNonEssentialMessages {
+			fmt.Printf("Generated UUID %s and saved it to %s\n", book.UUID(), configPath)
+		}
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		fmt.Printf("Done LOADING LIBRARY! (%d books)\n", len(library.Books))
+		fmt.Println("GENERATING STATIC WEBSITE...")
+	}
+
+	if err := pubhtml.RenderLibrary(&library, outputDir, layoutsDir); err != nil {
+		return err
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		fmt.Println("Done GENERATING STATIC WEBSITE...")
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	_ "time/tzdata" // book time zones must resolve on systems without a time zone database
 
 	"github.com/alecthomas/kong"
 	"github.com/goccy/go-yaml"
@@ -16,6 +17,10 @@ type Context struct {
 var CLI struct {
 	Book                   BookCommand    `cmd:"" help:"Create/manage a book project"`
 	Build                  BuildCommand   `cmd:"" default:"withargs" help:"Build structured source files"`
+	Export                 ExportCommand  `cmd:"" help:"Export a book in other formats"`
+	Library                LibraryCommand `cmd:"" help:"Build a library of books"`
+	Migrate                MigrateCommand `cmd:"" help:"Upgrade a book project's pub.yml and nav.yml to the current schema"`
+	Schema                 SchemaCommand  `cmd:"" help:"Print the JSON Schema of a configuration file, for editor completion and validation"`
 	Version                VersionCommand `cmd:"" help:"Print program version"`
 	Plain                  bool           `name:"plain" env:"NO_COLOR" help:"Disable escape codes such as colors and font styling from being printed to terminal output"`
 	NoNonEssentialMessages bool           `name:"no-non-essential-messages" short:"q" help:"Disable non-error and non-warning messages from being printed to terminal output"`
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/JessebotX/pub"
+)
+
+type MigrateCommand struct {
+	InputDirectory string `name:"input-directory" type:"existingdir" default:"./" arg:"" help:"Directory containing the book project to upgrade"`
+	DryRun         bool   `name:"dry-run" help:"Print the files that would be upgraded without changing them"`
+}
+
+func (m MigrateCommand) Run(ctx *Context) error {
+	changed, err := pub.MigrateBook(m.InputDirectory, m.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if ctx.NoNonEssentialMessages {
+		return nil
+	}
+
+	if len(changed) == 0 {
+		fmt.Printf("Book already uses the current schema (version %d)\n", pub.CurrentSchemaVersion)
+		return nil
+	}
+
+	verb := "Upgraded"
+	if m.DryRun {
+		verb = "Would upgrade"
+	}
+
+	for _, filePath := range changed {
+		fmt.Printf("%s %s to schema version %d\n", verb, filePath, pub.CurrentSchemaVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/JessebotX/pub"
+)
+
+type SchemaCommand struct {
+	File   string  `name:"file" arg:"" default:"pub.yml" enum:"pub.yml,nav.yml,reading_orders.yml,library.yml,series.yml" help:"Configuration file to describe (one of: ${enum})"`
+	Output *string `name:"output" short:"o" help:"File to write the JSON Schema to. By default, the schema is printed to standard output"`
+}
+
+func (s SchemaCommand) Run(ctx *Context) error {
+	var schema *pub.JSONSchema
+	switch s.File {
+	case pub.BookConfigFileName:
+		schema = pub.BookJSONSchema()
+	case pub.BookChaptersConfigFileName:
+		schema = pub.ChaptersJSONSchema()
+	case pub.BookReadingOrdersConfigFileName:
+		schema = pub.ReadingOrdersJSONSchema()
+	case pub.LibraryConfigFileName:
+		schema = pub.NewJSONSchema(pub.Library{})
+		schema.Title = pub.LibraryConfigFileName
+	case pub.SeriesConfigFileName:
+		schema = pub.NewJSONSchema([]pub.SeriesDefinition{})
+		schema.Title = pub.SeriesConfigFileName
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if s.Output == nil {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(*s.Output, data, 0666); err != nil {
+		return err
+	}
+
+	if !ctx.NoNonEssentialMessages {
+		fmt.Printf("Wrote the JSON Schema of %s to %s\n", s.File, *s.Output)
+	}
+
+	return nil
+}
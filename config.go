@@ -0,0 +1,392 @@
+package pub
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+const (
+	BookConfigExtendsKey       = "extends"
+	BookConfigIncludeKey       = "include"
+	BookConfigBuildProfilesKey = "build_profiles"
+
+	// BookConfigProfilesKey is the former name of [BookConfigBuildProfilesKey].
+	BookConfigProfilesKey = "profiles"
+)
+
+var (
+	ErrConfigOutsideBook = errors.New("file is outside of the book's directory")
+	ErrConfigNotMapping  = errors.New("config must be a mapping of keys to values")
+)
+
+type ErrConfigCycle struct {
+	Chain []string
+}
+
+func (e ErrConfigCycle) Error() string {
+	return fmt.Sprintf("config files extend or include each other in a cycle: %s", strings.Join(e.Chain, " -> "))
+}
+
+type ErrBuildProfileUnknown struct {
+	Name      string
+	Available []string
+}
+
+func (e ErrBuildProfileUnknown) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("build profile \"%s\" does not exist (no profiles are defined under \"%s\")", e.Name, BookConfigBuildProfilesKey)
+	}
+
+	return fmt.Sprintf("build profile \"%s\" does not exist (value must be one of the following: %s)", e.Name, strings.Join(e.Available, ", "))
+}
+
+type ErrEnvironmentVariableUnset struct {
+	Name string
+}
+
+func (e ErrEnvironmentVariableUnset) Error() string {
+	return fmt.Sprintf("environment variable \"%s\" is not set (use \"${%s:-default}\" to provide a default value)", e.Name, e.Name)
+}
+
+// bookConfig is the layout of pub.yml and the files it extends or includes, used
+// to check for unknown keys.
+type bookConfig struct {
+	Book
+	Extends       string          `json:"extends"`
+	Include       []string        `json:"include"`
+	BuildProfiles map[string]Book `json:"build_profiles"`
+
+	// Profiles is an alias of BuildProfiles.
+	Profiles map[string]Book `json:"profiles"`
+}
+
+// configLayer is a config file that is part of a book's config, in the order the files are merged.
+type configLayer struct {
+	name      string // path in the book's file system
+	inputPath string
+	entries   []*ast.MappingValueNode
+}
+
+// envExcludedKeys are the keys whose values do not have environment variables expanded.
+var envExcludedKeys = []string{"content", "extra"}
+
+// loadConfig decodes the book's config from pub.yml at name, merging each of
+// overrides (e.g. the pub.yml of a translation) over it.
+//
+// Files are deep-merged in order: "extends", "include", the file itself, and the
+// build profile. "${NAME}" is replaced by the environment variable NAME.
+func (b *Book) loadConfig(name string, overrides ...string) error {
+	raw, err := b.readConfigFile(name)
+	if err != nil {
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(name), err)
+	}
+
+	// pub.yml sets the schema version of files that cannot declare one (e.g. nav.yml)
+	version, err := schemaVersionOf(raw)
+	if err != nil {
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(name), err)
+	}
+	b.sourceSchema = version
+
+	layers, err := b.configLayers(name, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, override := range overrides {
+		overrideLayers, err := b.configLayers(override, nil)
+		if err != nil {
+			return err
+		}
+		layers = append(layers, overrideLayers...)
+	}
+
+	var merged []*ast.MappingValueNode
+	var baseLoc, profileLoc location
+	var profileFound bool
+	var available []string
+	for i := len(layers) - 1; i >= 0; i-- {
+		baseLoc = append(baseLoc, at(layers[i].inputPath, "$")...)
+	}
+
+	for _, layer := range layers {
+		profiles, _ := buildProfiles(layer.entries)
+		for _, profile := range profiles {
+			available = append(available, profile.Key.GetToken().Value)
+		}
+
+		base := slices.DeleteFunc(slices.Clone(layer.entries), func(entry *ast.MappingValueNode) bool {
+			return slices.Contains([]string{BookConfigExtendsKey, BookConfigIncludeKey, BookConfigBuildProfilesKey, BookConfigProfilesKey}, entry.Key.GetToken().Value)
+		})
+		merged = mergeConfig(merged, base)
+	}
+
+	if b.options.profile != "" {
+		for i := len(layers) - 1; i >= 0; i-- {
+			_, key := buildProfiles(layers[i].entries)
+			profileLoc = append(profileLoc, at(layers[i].inputPath, "$."+key+"."+b.options.profile)...)
+		}
+
+		for _, layer := range layers {
+			profiles, _ := buildProfiles(layer.entries)
+			profile := configEntry(profiles, b.options.profile)
+			if profile.Key == nil {
+				continue
+			}
+			profileFound = true
+
+			merged = mergeConfig(merged, mappingEntries(profile.Value))
+		}
+
+		if !profileFound {
+			slices.Sort(available)
+			return ErrBuildProfileUnknown{Name: b.options.profile, Available: slices.Compact(available)}
+		}
+	}
+	b.BuildProfile = b.options.profile
+	b.loc = append(profileLoc, baseLoc...)
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	// the first candidate of the location is the file the value was merged from
+	for _, entry := range merged {
+		key := entry.Key.GetToken().Value
+		if !slices.Contains(envExcludedKeys, key) {
+			b.expandEnv(entry.Value, b.loc.field(key))
+		}
+	}
+
+	if err := yaml.NodeToValue(ast.Mapping(merged[0].GetToken(), false, merged...), b); err != nil {
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(name), err)
+	}
+
+	return nil
+}
+
+// configLayers returns the config file at name and the files it extends or
+// includes, in merge order. chain is used to detect cycles.
+func (b *Book) configLayers(name string, chain []string) ([]configLayer, error) {
+	if slices.Contains(chain, name) {
+		return nil, ErrConfigCycle{Chain: append(chain, name)}
+	}
+	chain = append(chain, name)
+
+	raw, err := b.readConfigFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("parsing \"%s\": %w", name, err)
+	}
+
+	// each config file is upgraded from its own version, as it may be shared by books
+	version, err := schemaVersionOf(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing \"%s\": %w", name, err)
+	}
+
+	if version < CurrentSchemaVersion {
+		raw, err = migrateYAML(raw, version, &Book{})
+		if err != nil {
+			return nil, fmt.Errorf("parsing \"%s\": %w", name, err)
+		}
+	}
+
+	inputPath := b.inputPathOf(name)
+	b.addSource(inputPath, raw, 0, reflect.TypeFor[bookConfig]())
+
+	f, err := parser.ParseBytes(raw, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing \"%s\": %w", name, err)
+	}
+
+	var entries []*ast.MappingValueNode
+	if len(f.Docs) > 0 && f.Docs[0].Body != nil {
+		entries = mappingEntries(f.Docs[0].Body)
+		if entries == nil {
+			return nil, fmt.Errorf("parsing \"%s\": %w", name, ErrConfigNotMapping)
+		}
+	}
+
+	var header struct {
+		Extends string   `json:"extends"`
+		Include []string `json:"include"`
+	}
+	if err := yaml.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("parsing \"%s\": %w", name, err)
+	}
+
+	var parents []string
+	if header.Extends != "" {
+		parents = append(parents, header.Extends)
+	}
+	parents = append(parents, header.Include...)
+
+	var layers []configLayer
+	for _, parent := range parents {
+		parentLayers, err := b.configLayers(path.Join(path.Dir(name), filepath.ToSlash(parent)), chain)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, parentLayers...)
+	}
+
+	return append(layers, configLayer{name: name, inputPath: inputPath, entries: entries}), nil
+}
+
+// readConfigFile reads the file at name, which may be outside of the book's file
+// system for books loaded with [NewBook].
+func (b *Book) readConfigFile(name string) ([]byte, error) {
+	name = path.Clean(name)
+	if fs.ValidPath(name) {
+		return fs.ReadFile(b.fsys, name)
+	}
+
+	if b.options.baseDir == "" {
+		return nil, ErrConfigOutsideBook
+	}
+
+	return os.ReadFile(filepath.Join(b.options.baseDir, filepath.FromSlash(name)))
+}
+
+// mergeConfig returns the entries of the mapping base deep-merged with override.
+func mergeConfig(base, override []*ast.MappingValueNode) []*ast.MappingValueNode {
+	merged := slices.Clone(base)
+	for _, entry := range override {
+		key := entry.Key.GetToken().Value
+		i := slices.IndexFunc(merged, func(existing *ast.MappingValueNode) bool { return existing.Key.GetToken().Value == key })
+		if i < 0 {
+			merged = append(merged, entry)
+			continue
+		}
+
+		baseEntries, overrideEntries := mappingEntries(merged[i].Value), mappingEntries(entry.Value)
+		if baseEntries == nil || overrideEntries == nil {
+			merged[i] = entry
+			continue
+		}
+
+		values := mergeConfig(baseEntries, overrideEntries)
+		merged[i] = ast.MappingValue(entry.GetToken(), entry.Key, ast.Mapping(entry.Value.GetToken(), false, values...))
+	}
+
+	return merged
+}
+
+// buildProfiles returns the build profiles of a config file and their key.
+func buildProfiles(entries []*ast.MappingValueNode) ([]*ast.MappingValueNode, string) {
+	if entry := configEntry(entries, BookConfigProfilesKey); entry.Key != nil && configEntry(entries, BookConfigBuildProfilesKey).Key == nil {
+		return mappingEntries(entry.Value), BookConfigProfilesKey
+	}
+
+	return mappingEntries(configEntry(entries, BookConfigBuildProfilesKey).Value), BookConfigBuildProfilesKey
+}
+
+// configEntry returns the entry with key of the mapping entries, if any.
+func configEntry(entries []*ast.MappingValueNode, key string) *ast.MappingValueNode {
+	for _, entry := range entries {
+		if entry.Key.GetToken().Value == key {
+			return entry
+		}
+	}
+
+	return &ast.MappingValueNode{}
+}
+
+// expandEnv replaces environment variables in every string of the node at loc.
+func (b *Book) expandEnv(node ast.Node, loc location) {
+	switch n := node.(type) {
+	case *ast.StringNode:
+		expanded, unset := expandEnvString(n.Value)
+		for _, name := range unset {
+			b.deferred = append(b.deferred, deferredError{loc: loc, err: ErrEnvironmentVariableUnset{Name: name}})
+		}
+		if expanded != n.Value {
+			// custom unmarshalers (e.g. of dates) read the source of the token
+			tk := *n.Token
+			tk.Type, tk.Value, tk.Origin = token.DoubleQuoteType, expanded, strconv.Quote(expanded)
+			tk.Prev, tk.Next = nil, nil
+			n.Token, n.Value = &tk, expanded
+		}
+	case *ast.LiteralNode:
+		b.expandEnv(n.Value, loc)
+	case *ast.TagNode:
+		b.expandEnv(n.Value, loc)
+	case *ast.AnchorNode:
+		b.expandEnv(n.Value, loc)
+	case *ast.MappingNode, *ast.MappingValueNode:
+		for _, entry := range mappingEntries(n) {
+			b.expandEnv(entry.Value, loc.field(entry.Key.GetToken().Value))
+		}
+	case *ast.SequenceNode:
+		for i, value := range n.Values {
+			b.expandEnv(value, loc.join(fmt.Sprintf("[%d]", i)))
+		}
+	}
+}
+
+// expandEnvString replaces "${NAME}" and "${NAME:-default}" in s, returning the
+// unset variables without a default.
+func expandEnvString(s string) (string, []string) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var unset []string
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+
+		b.WriteString(s[:i])
+		name, fallback, hasFallback := strings.Cut(s[i+2:i+end], ":-")
+		value := os.Getenv(name)
+		switch {
+		case value != "":
+		case hasFallback:
+			value = fallback
+		default:
+			if _, ok := os.LookupEnv(name); !ok {
+				unset = append(unset, name)
+			}
+		}
+		b.WriteString(value)
+
+		s = s[i+end+1:]
+	}
+
+	return b.String(), unset
+}
+
+// deferredError is a problem found while loading a book, reported on validation.
+type deferredError struct {
+	loc location
+	err error
+}
@@ -0,0 +1,160 @@
+package pub
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+func TestMergeConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		base, override string
+		want           map[string]any
+	}{
+		{
+			name:     "new keys are added",
+			base:     "title: A\n",
+			override: "subtitle: B\n",
+			want:     map[string]any{"title": "A", "subtitle": "B"},
+		},
+		{
+			name:     "scalars are replaced",
+			base:     "title: A\nsubtitle: B\n",
+			override: "title: C\n",
+			want:     map[string]any{"title": "C", "subtitle": "B"},
+		},
+		{
+			name:     "mappings are merged",
+			base:     "extra:\n  a: 1\n  b:\n    c: 2\n    d: 3\n",
+			override: "extra:\n  b:\n    d: 4\n  e: 5\n",
+			want:     map[string]any{"extra": map[string]any{"a": uint64(1), "b": map[string]any{"c": uint64(2), "d": uint64(4)}, "e": uint64(5)}},
+		},
+		{
+			name:     "flow mappings are merged",
+			base:     "extra: {a: 1, b: 2}\n",
+			override: "extra:\n  b: 3\n",
+			want:     map[string]any{"extra": map[string]any{"a": uint64(1), "b": uint64(3)}},
+		},
+		{
+			name:     "sequences are replaced",
+			base:     "tags: [a, b]\n",
+			override: "tags: [c]\n",
+			want:     map[string]any{"tags": []any{"c"}},
+		},
+		{
+			name:     "a mapping replaces a scalar",
+			base:     "extra: none\n",
+			override: "extra:\n  a: 1\n",
+			want:     map[string]any{"extra": map[string]any{"a": uint64(1)}},
+		},
+		{
+			name:     "null replaces a mapping",
+			base:     "extra:\n  a: 1\n",
+			override: "extra:\n",
+			want:     map[string]any{"extra": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, override := parseConfigEntries(t, tt.base), parseConfigEntries(t, tt.override)
+			baseText := ast.Mapping(base[0].GetToken(), false, base...).String()
+
+			merged := mergeConfig(base, override)
+
+			var got map[string]any
+			if err := yaml.NodeToValue(ast.Mapping(merged[0].GetToken(), false, merged...), &got); err != nil {
+				t.Fatalf("NodeToValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeConfig() = %#v, want %#v", got, tt.want)
+			}
+
+			if text := ast.Mapping(base[0].GetToken(), false, base...).String(); text != baseText {
+				t.Errorf("mergeConfig() modified base: %q, was %q", text, baseText)
+			}
+		})
+	}
+}
+
+func TestBuildProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		want    string
+		wantErr bool
+	}{
+		{"no profile", "title: Base\nbuild_profiles:\n  preview:\n    title: Preview\n", "", "Base", false},
+		{"build_profiles", "title: Base\nbuild_profiles:\n  preview:\n    title: Preview\n", "preview", "Preview", false},
+		{"profiles alias", "title: Base\nprofiles:\n  preview:\n    title: Preview\n", "preview", "Preview", false},
+		{"build_profiles wins over the alias", "title: Base\nprofiles:\n  preview:\n    title: Alias\nbuild_profiles:\n  preview:\n    title: Preview\n", "preview", "Preview", false},
+		{"unknown profile", "title: Base\nbuild_profiles:\n  preview:\n    title: Preview\n", "production", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"book/pub.yml": {Data: []byte("unique_id: a\nlanguage_code: en\n" + tt.config)},
+			}
+
+			book, err := NewBookFS(fsys, "book", WithProfile(tt.profile))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBookFS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && book.Title != tt.want {
+				t.Errorf("Title = %q, want %q", book.Title, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandEnvString(t *testing.T) {
+	t.Setenv("PUB_TEST_SET", "value")
+	t.Setenv("PUB_TEST_EMPTY", "")
+
+	tests := []struct {
+		s     string
+		want  string
+		unset []string
+	}{
+		{"plain", "plain", nil},
+		{"${PUB_TEST_SET}", "value", nil},
+		{"a ${PUB_TEST_SET} b ${PUB_TEST_SET}", "a value b value", nil},
+		{"${PUB_TEST_SET:-default}", "value", nil},
+		{"${PUB_TEST_EMPTY:-default}", "default", nil},
+		{"${PUB_TEST_UNSET:-default}", "default", nil},
+		{"${PUB_TEST_UNSET:-}", "", nil},
+		{"${PUB_TEST_EMPTY}", "", nil},
+		{"${PUB_TEST_UNSET}", "", []string{"PUB_TEST_UNSET"}},
+		{"$${PUB_TEST_SET}", "${PUB_TEST_SET}", nil},
+		{"$PUB_TEST_SET", "$PUB_TEST_SET", nil},
+		{"${PUB_TEST_SET", "${PUB_TEST_SET", nil},
+		{"${PUB_TEST_SET}${PUB_TEST_UNSET}", "value", []string{"PUB_TEST_UNSET"}},
+	}
+
+	for _, tt := range tests {
+		got, unset := expandEnvString(tt.s)
+		if got != tt.want || !slices.Equal(unset, tt.unset) {
+			t.Errorf("expandEnvString(%q) = %q, %q; want %q, %q", tt.s, got, unset, tt.want, tt.unset)
+		}
+	}
+}
+
+// parseConfigEntries returns the entries of the YAML mapping raw.
+func parseConfigEntries(t *testing.T, raw string) []*ast.MappingValueNode {
+	t.Helper()
+
+	f, err := parser.ParseBytes([]byte(raw), 0)
+	if err != nil {
+		t.Fatalf("ParseBytes(%q) error = %v", raw, err)
+	}
+
+	return mappingEntries(f.Docs[0].Body)
+}
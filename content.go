@@ -1,9 +1,12 @@
 package pub
 
 import (
-	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+
+	"github.com/goccy/go-yaml"
 )
 
 var (
@@ -12,35 +15,147 @@ var (
 )
 
 // Content represents a body of text that is/can be parsed into different formats (e.g. Markdown to HTML, etc.).
+//
+// Lazily loaded content keeps Raw empty until it is read with [Content.Bytes].
 type Content struct {
 	Raw []byte
 
 	parsed map[string]any
+	load   func() ([]byte, error)
+	size   int
+}
+
+// lazyContent returns content that is read with load on first access.
+func lazyContent(load func() ([]byte, error), size int) Content {
+	return Content{load: load, size: size}
 }
 
-func (c *Content) MarshalText() ([]byte, error) {
+// Bytes returns the raw content, reading it first if it is lazily loaded and not in memory.
+func (c *Content) Bytes() ([]byte, error) {
+	if c.Raw == nil && c.load != nil {
+		raw, err := c.load()
+		if err != nil {
+			return nil, err
+		}
+		c.Raw = raw
+		c.size = len(raw)
+	}
+
 	return c.Raw, nil
 }
 
-func (c *Content) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf("\"%s\"", string(c.Raw))), nil
+// Len returns the length of the raw content, without reading lazily loaded content.
+func (c *Content) Len() int {
+	if c.Raw == nil && c.load != nil {
+		return c.size
+	}
+
+	return len(c.Raw)
+}
+
+// Release frees lazily loaded content until the next call to [Content.Bytes].
+func (c *Content) Release() {
+	if c.load == nil {
+		return
+	}
+
+	c.Raw = nil
+	c.parsed = nil
+}
+
+// clone returns a copy of the content with its own parsed formats.
+func (c Content) clone() Content {
+	c.parsed = maps.Clone(c.parsed)
+
+	return c
+}
+
+// contentWithFormats is the layout of marshaled content with parsed formats.
+type contentWithFormats struct {
+	Raw     string            `json:"raw"`
+	Formats map[string]string `json:"formats"`
+}
+
+// marshaled returns the content as a string, or as a [contentWithFormats].
+func (c *Content) marshaled() (any, error) {
+	raw, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.parsed) == 0 {
+		return string(raw), nil
+	}
+
+	formats := make(map[string]string, len(c.parsed))
+	for format, parsed := range c.parsed {
+		formats[format] = fmt.Sprint(parsed)
+	}
+
+	return contentWithFormats{Raw: string(raw), Formats: formats}, nil
+}
+
+func (c *Content) unmarshaled(v contentWithFormats) {
+	c.Raw, c.load = []byte(v.Raw), nil
+
+	c.parsed = nil
+	for format, parsed := range v.Formats {
+		c.AddFormat(format, parsed)
+	}
+}
+
+func (c Content) MarshalText() ([]byte, error) {
+	return c.Bytes()
+}
+
+func (c Content) MarshalJSON() ([]byte, error) {
+	v, err := c.marshaled()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
 }
 
-func (c *Content) MarshalYAML() ([]byte, error) {
-	return []byte(fmt.Sprintf("\"%s\"", string(c.Raw))), nil
+func (c Content) MarshalYAML() (any, error) {
+	return c.marshaled()
 }
 
 func (c *Content) UnmarshalText(text []byte) error {
-	c.Raw = text
+	c.Raw, c.load = text, nil
 	return nil
 }
 
+// UnmarshalJSON decodes the content from a string, or from an object with formats.
 func (c *Content) UnmarshalJSON(text []byte) error {
-	return c.UnmarshalText(bytes.TrimPrefix(bytes.TrimSuffix(text, []byte("\"")), []byte("\"")))
+	var s string
+	if err := json.Unmarshal(text, &s); err == nil {
+		return c.UnmarshalText([]byte(s))
+	}
+
+	var v contentWithFormats
+	if err := json.Unmarshal(text, &v); err != nil {
+		return err
+	}
+	c.unmarshaled(v)
+
+	return nil
 }
 
+// UnmarshalYAML decodes the content from a string, or from a mapping with formats.
 func (c *Content) UnmarshalYAML(text []byte) error {
-	return c.UnmarshalText(bytes.TrimPrefix(bytes.TrimSuffix(text, []byte("\"")), []byte("\"")))
+	var s string
+	if err := yaml.Unmarshal(text, &s); err == nil {
+		return c.UnmarshalText([]byte(s))
+	}
+
+	var v contentWithFormats
+	if err := yaml.Unmarshal(text, &v); err != nil {
+		return err
+	}
+	c.unmarshaled(v)
+
+	return nil
 }
 
 func (c *Content) Format(format string) (any, error) {
@@ -0,0 +1,110 @@
+package pub
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestContentRelease(t *testing.T) {
+	tests := []struct {
+		name      string
+		lazy      bool
+		wantLoads int
+		wantRaw   bool
+	}{
+		{"lazy", true, 2, false},
+		{"eager", false, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loads := 0
+			content := Content{Raw: []byte("body")}
+			if tt.lazy {
+				content = lazyContent(func() ([]byte, error) {
+					loads++
+					return []byte("body"), nil
+				}, 4)
+			}
+
+			if content.Len() != 4 {
+				t.Errorf("Len() = %d, want 4", content.Len())
+			}
+			if raw, err := content.Bytes(); err != nil || string(raw) != "body" {
+				t.Fatalf("Bytes() = %q, %v, want \"body\"", raw, err)
+			}
+			content.AddFormat("html", "<p>body</p>")
+
+			content.Release()
+			if (content.Raw != nil) != tt.wantRaw {
+				t.Errorf("Release() kept Raw = %q, want kept %t", content.Raw, tt.wantRaw)
+			}
+			if _, err := content.Format("html"); (err == nil) != tt.wantRaw {
+				t.Errorf("Release() Format(\"html\") error = %v, want kept %t", err, tt.wantRaw)
+			}
+			if content.Len() != 4 {
+				t.Errorf("Len() after Release() = %d, want 4", content.Len())
+			}
+
+			if raw, err := content.Bytes(); err != nil || string(raw) != "body" {
+				t.Errorf("Bytes() after Release() = %q, %v, want \"body\"", raw, err)
+			}
+			if loads != tt.wantLoads {
+				t.Errorf("content read %d times, want %d", loads, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestLazyChapterContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no front matter", "Body\n", "Body\n"},
+		{"front matter", "---\ntitle: One\n---\nBody\n", "Body\n"},
+		{"authors' note", "---\ntitle: One\n---\nBody\n<!-- note-after -->\nThanks\n<!-- /note-after -->\n", "Body\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"book/pub.yml":         {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+				"book/nav.yml":         {Data: []byte("- content_file_name: one.md\n")},
+				"book/chapters/one.md": {Data: []byte(tt.content)},
+			}
+
+			eager, err := NewBookFS(fsys, "book")
+			if err != nil {
+				t.Fatalf("NewBookFS() error = %v", err)
+			}
+			book, err := NewBookFS(fsys, "book", WithLazyContent())
+			if err != nil {
+				t.Fatalf("NewBookFS(WithLazyContent()) error = %v", err)
+			}
+
+			content := &book.Chapters[0].Content
+			if content.Raw != nil {
+				t.Errorf("Raw = %q before Bytes(), want nil", content.Raw)
+			}
+			if got := string(eager.Chapters[0].Content.Raw); got != tt.want {
+				t.Fatalf("eager Raw = %q, want %q", got, tt.want)
+			}
+			if content.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", content.Len(), len(tt.want))
+			}
+
+			for range 2 {
+				raw, err := content.Bytes()
+				if err != nil {
+					t.Fatalf("Bytes() error = %v", err)
+				}
+				if string(raw) != tt.want {
+					t.Errorf("Bytes() = %q, want %q", raw, tt.want)
+				}
+				content.Release()
+			}
+		})
+	}
+}
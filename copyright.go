@@ -1,11 +1,124 @@
 package pub
 
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
 type Copyright struct {
 	Notice   string    `json:"notice"`
 	Licenses []License `json:"licenses"`
 }
 
+// License is a license that applies to a [Book] or [Chapter]. Its text is set
+// inline, read from FileName, or filled in from a known SPDX identifier.
 type License struct {
+	SPDX     string `json:"spdx"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
 	FileName string `json:"file_name"`
 	Text     string `json:"text"`
 }
+
+// Slug returns the license's identifier for file names and URLs (e.g. "cc-by-4.0").
+func (l License) Slug() string {
+	var slug string
+	switch {
+	case l.SPDX != "":
+		slug = l.SPDX
+	case l.FileName != "":
+		slug = strings.TrimSuffix(path.Base(l.FileName), path.Ext(l.FileName))
+	default:
+		slug = l.Name
+	}
+
+	return slugify(slug)
+}
+
+// normalize fills in the empty fields of the license from its SPDX identifier.
+func (l *License) normalize() {
+	known, ok := spdxLicenseByID(l.SPDX)
+	if !ok {
+		return
+	}
+	l.SPDX = known.SPDX
+
+	if l.Name == "" {
+		l.Name = known.Name
+	}
+
+	if l.URL == "" {
+		l.URL = known.URL
+	}
+
+	if l.Text == "" && l.FileName == "" {
+		l.Text = known.Text
+	}
+}
+
+func (l License) validate(v *validator, loc location) {
+	if _, ok := spdxLicenseByID(l.SPDX); l.SPDX != "" && !ok {
+		v.error(loc.field("spdx"), ErrLicenseUnknownSPDX{SPDX: l.SPDX})
+	}
+}
+
+type ErrLicenseUnknownSPDX struct {
+	SPDX string
+}
+
+func (e ErrLicenseUnknownSPDX) Error() string {
+	return fmt.Sprintf("license: unrecognized SPDX identifier \"%s\" (value must be one of the following (case doesn't matter): %s)", e.SPDX, strings.Join(slices.Sorted(maps.Keys(SPDXLicenses)), ", "))
+}
+
+//go:embed licenses/*.txt
+var licenseTexts embed.FS
+
+// SPDXLicenses are the licenses that [License].SPDX may reference.
+var SPDXLicenses = map[string]License{
+	"CC0-1.0":           spdxLicense("CC0-1.0", "Creative Commons Zero v1.0 Universal", "https://creativecommons.org/publicdomain/zero/1.0/legalcode", "CC0-1.0"),
+	"CC-BY-4.0":         spdxLicense("CC-BY-4.0", "Creative Commons Attribution 4.0 International", "https://creativecommons.org/licenses/by/4.0/legalcode", "CC-BY-4.0"),
+	"CC-BY-SA-4.0":      spdxLicense("CC-BY-SA-4.0", "Creative Commons Attribution-ShareAlike 4.0 International", "https://creativecommons.org/licenses/by-sa/4.0/legalcode", "CC-BY-SA-4.0"),
+	"CC-BY-ND-4.0":      spdxLicense("CC-BY-ND-4.0", "Creative Commons Attribution-NoDerivatives 4.0 International", "https://creativecommons.org/licenses/by-nd/4.0/legalcode", "CC-BY-ND-4.0"),
+	"CC-BY-NC-4.0":      spdxLicense("CC-BY-NC-4.0", "Creative Commons Attribution-NonCommercial 4.0 International", "https://creativecommons.org/licenses/by-nc/4.0/legalcode", "CC-BY-NC-4.0"),
+	"CC-BY-NC-SA-4.0":   spdxLicense("CC-BY-NC-SA-4.0", "Creative Commons Attribution-NonCommercial-ShareAlike 4.0 International", "https://creativecommons.org/licenses/by-nc-sa/4.0/legalcode", "CC-BY-NC-SA-4.0"),
+	"CC-BY-NC-ND-4.0":   spdxLicense("CC-BY-NC-ND-4.0", "Creative Commons Attribution-NonCommercial-NoDerivatives 4.0 International", "https://creativecommons.org/licenses/by-nc-nd/4.0/legalcode", "CC-BY-NC-ND-4.0"),
+	"MIT":               spdxLicense("MIT", "MIT License", "https://opensource.org/license/mit", "MIT"),
+	"BSD-3-Clause":      spdxLicense("BSD-3-Clause", "BSD 3-Clause \"New\" or \"Revised\" License", "https://opensource.org/license/bsd-3-clause", "BSD-3-Clause"),
+	"BSL-1.0":           spdxLicense("BSL-1.0", "Boost Software License 1.0", "https://www.boost.org/LICENSE_1_0.txt", "BSL-1.0"),
+	"Apache-2.0":        spdxLicense("Apache-2.0", "Apache License 2.0", "https://www.apache.org/licenses/LICENSE-2.0", "Apache-2.0"),
+	"MPL-2.0":           spdxLicense("MPL-2.0", "Mozilla Public License 2.0", "https://www.mozilla.org/en-US/MPL/2.0/", "MPL-2.0"),
+	"GPL-3.0-only":      spdxLicense("GPL-3.0-only", "GNU General Public License v3.0 only", "https://www.gnu.org/licenses/gpl-3.0.html", "GPL-3.0"),
+	"GPL-3.0-or-later":  spdxLicense("GPL-3.0-or-later", "GNU General Public License v3.0 or later", "https://www.gnu.org/licenses/gpl-3.0.html", "GPL-3.0"),
+	"LGPL-3.0-only":     spdxLicense("LGPL-3.0-only", "GNU Lesser General Public License v3.0 only", "https://www.gnu.org/licenses/lgpl-3.0.html", "LGPL-3.0"),
+	"GFDL-1.3-only":     spdxLicense("GFDL-1.3-only", "GNU Free Documentation License v1.3 only", "https://www.gnu.org/licenses/fdl-1.3.html", "GFDL-1.3"),
+	"GFDL-1.3-or-later": spdxLicense("GFDL-1.3-or-later", "GNU Free Documentation License v1.3 or later", "https://www.gnu.org/licenses/fdl-1.3.html", "GFDL-1.3"),
+}
+
+// spdxLicenseByID returns the license of [SPDXLicenses] with the given ID in any case.
+func spdxLicenseByID(id string) (License, bool) {
+	id = strings.TrimSpace(id)
+	if known, ok := SPDXLicenses[id]; ok {
+		return known, true
+	}
+
+	for key, known := range SPDXLicenses {
+		if strings.EqualFold(key, id) {
+			return known, true
+		}
+	}
+
+	return License{}, false
+}
+
+func spdxLicense(id, name, url, textName string) License {
+	text, err := licenseTexts.ReadFile(path.Join("licenses", textName+".txt"))
+	if err != nil {
+		panic(err)
+	}
+
+	return License{SPDX: id, Name: name, URL: url, Text: string(text)}
+}
@@ -0,0 +1,137 @@
+package pub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSPDXLicenseByID(t *testing.T) {
+	tests := []struct {
+		id     string
+		want   string
+		wantOk bool
+	}{
+		{"CC-BY-4.0", "CC-BY-4.0", true},
+		{"cc-by-4.0", "CC-BY-4.0", true},
+		{" mit ", "MIT", true},
+		{"gpl-3.0-or-later", "GPL-3.0-or-later", true},
+		{"CC-BY-5.0", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := spdxLicenseByID(tt.id)
+		if ok != tt.wantOk || got.SPDX != tt.want {
+			t.Errorf("spdxLicenseByID(%q) = %q, %t, want %q, %t", tt.id, got.SPDX, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestSPDXLicensesHaveText(t *testing.T) {
+	for id, license := range SPDXLicenses {
+		if license.SPDX != id || license.Name == "" || license.URL == "" || strings.TrimSpace(license.Text) == "" {
+			t.Errorf("SPDXLicenses[%q] = %q, %q, %q with %d bytes of text", id, license.SPDX, license.Name, license.URL, len(license.Text))
+		}
+	}
+}
+
+func TestLicenseNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		license License
+		want    License
+	}{
+		{"spdx", License{SPDX: "mit"}, License{SPDX: "MIT", Name: SPDXLicenses["MIT"].Name, URL: SPDXLicenses["MIT"].URL, Text: SPDXLicenses["MIT"].Text}},
+		{"fields kept", License{SPDX: "MIT", Name: "Expat", Text: "custom"}, License{SPDX: "MIT", Name: "Expat", URL: SPDXLicenses["MIT"].URL, Text: "custom"}},
+		{"file name kept", License{SPDX: "MIT", FileName: "LICENSE.txt"}, License{SPDX: "MIT", Name: SPDXLicenses["MIT"].Name, URL: SPDXLicenses["MIT"].URL, FileName: "LICENSE.txt"}},
+		{"unknown", License{SPDX: "Unknown-1.0"}, License{SPDX: "Unknown-1.0"}},
+		{"no spdx", License{Name: "Custom", Text: "text"}, License{Name: "Custom", Text: "text"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.license
+			got.normalize()
+			if got != tt.want {
+				t.Errorf("normalize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLicenseSlug(t *testing.T) {
+	tests := []struct {
+		license License
+		want    string
+	}{
+		{License{SPDX: "CC-BY-4.0"}, "cc-by-4.0"},
+		{License{FileName: "legal/My License.txt"}, "my-license"},
+		{License{Name: "House Rules"}, "house-rules"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.license.Slug(); got != tt.want {
+			t.Errorf("%+v.Slug() = %q, want %q", tt.license, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLicenseTexts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":            {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\ncopyright:\n  licenses:\n    - file_name: LICENSE.txt\n    - file_name: NOTICE.txt\n    - spdx: cc0-1.0\n")},
+		"book/assets/LICENSE.txt": {Data: []byte("from assets")},
+		"book/LICENSE.txt":        {Data: []byte("from book")},
+		"book/NOTICE.txt":         {Data: []byte("notice")},
+		"book/nav.yml":            {Data: []byte("- content_file_name: one.md\n")},
+		"book/chapters/one.md":    {Data: []byte("---\ncopyright:\n  licenses:\n    - spdx: mit\n---\nOne\n")},
+	}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		license License
+		want    string
+	}{
+		{"assets first", book.Copyright.Licenses[0], "from assets"},
+		{"book directory", book.Copyright.Licenses[1], "notice"},
+		{"spdx", book.Copyright.Licenses[2], SPDXLicenses["CC0-1.0"].Text},
+		{"chapter", book.Chapters[0].Copyright.Licenses[0], SPDXLicenses["MIT"].Text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.license.Text != tt.want {
+				t.Errorf("Text = %.40q, want %.40q", tt.license.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLicenseTextsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		pubYML string
+		want   error
+	}{
+		{"unknown spdx", "unique_id: a\ntitle: A\nlanguage_code: en\ncopyright:\n  licenses:\n    - spdx: CC-BY-5.0\n", ErrLicenseUnknownSPDX{SPDX: "CC-BY-5.0"}},
+		{"missing file", "unique_id: a\ntitle: A\nlanguage_code: en\ncopyright:\n  licenses:\n    - file_name: MISSING.txt\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(fstest.MapFS{"book/pub.yml": {Data: []byte(tt.pubYML)}}, "book")
+			if err == nil {
+				t.Fatalf("NewBookFS() error = nil, want an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("NewBookFS() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/goccy/go-yaml"
 )
 
 var (
@@ -29,22 +33,89 @@ var (
 	}
 )
 
+// DateTimePrecision is the smallest unit of time a [DateTime] was written with.
+type DateTimePrecision int
+
+const (
+	PrecisionSecond DateTimePrecision = iota
+	PrecisionMinute
+	PrecisionDay
+	PrecisionMonth
+	PrecisionYear
+)
+
+var (
+	DateTimePrecisionMap = map[string]DateTimePrecision{
+		"second": PrecisionSecond,
+		"minute": PrecisionMinute,
+		"day":    PrecisionDay,
+		"month":  PrecisionMonth,
+		"year":   PrecisionYear,
+	}
+)
+
+func (p DateTimePrecision) String() string {
+	for k, v := range DateTimePrecisionMap {
+		if v == p {
+			return k
+		}
+	}
+
+	return strconv.Itoa(int(p))
+}
+
+// layout returns the layout of the precision, with sep before the time of day.
+func (p DateTimePrecision) layout(sep string, floating bool) string {
+	var layout string
+	switch p {
+	case PrecisionYear:
+		layout = "2006"
+	case PrecisionMonth:
+		layout = "2006-01"
+	case PrecisionDay:
+		layout = "2006-01-02"
+	case PrecisionMinute:
+		layout = "2006-01-02" + sep + "15:04"
+	default:
+		layout = "2006-01-02" + sep + "15:04:05"
+	}
+
+	if floating {
+		return layout
+	}
+
+	if p >= PrecisionDay {
+		// e.g. "2006-01-02TZ07:00"
+		return layout + sep + "Z07:00"
+	}
+
+	return layout + "Z07:00"
+}
+
+// DateTime is a point in time that is formatted as precisely as it was written.
+// Without a time zone, it is floating until the book's TimeZone is applied.
 type DateTime struct {
 	time.Time
+	Precision DateTimePrecision
+
+	floating bool
+}
+
+func (d DateTime) String() string {
+	return d.Format(d.Precision.layout(" ", d.floating))
 }
 
 func (d DateTime) MarshalText() ([]byte, error) {
-	timeString := d.Format("2006-01-02 15:04:05Z07:00")
-	return []byte(timeString), nil
+	return []byte(d.String()), nil
 }
 
 func (d DateTime) MarshalJSON() ([]byte, error) {
-	timeString := fmt.Sprintf("\"%s\"", d.Format("2006-01-02T15:04:05Z07:00"))
+	timeString := fmt.Sprintf("\"%s\"", d.Format(d.Precision.layout("T", d.floating)))
 	return []byte(timeString), nil
 }
 
 func (d DateTime) MarshalYAML() ([]byte, error) {
-	timeString := fmt.Sprintf("\"%s\"", d.Format("2006-01-02T15:04:05Z07:00"))
+	timeString := fmt.Sprintf("\"%s\"", d.Format(d.Precision.layout("T", d.floating)))
 	return []byte(timeString), nil
 }
 
@@ -65,19 +136,121 @@ func (d *DateTime) UnmarshalJSON(text []byte) error {
 }
 
 func (d *DateTime) UnmarshalYAML(text []byte) error {
-	return d.UnmarshalText(bytes.TrimPrefix(bytes.TrimSuffix(text, []byte("\"")), []byte("\"")))
+	var s string
+	if err := yaml.Unmarshal(text, &s); err != nil {
+		return err
+	}
+
+	return d.UnmarshalText([]byte(s))
 }
 
+// IsFloating reports whether the date and time was written without a time zone.
+func (d DateTime) IsFloating() bool {
+	return d.floating
+}
+
+// setDefaultLocation moves a floating d to the same wall clock time in loc.
+func (d *DateTime) setDefaultLocation(loc *time.Location) {
+	if d == nil || !d.floating {
+		return
+	}
+
+	d.Time = time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), loc)
+	d.floating = false
+}
+
+// Localized formats the date and time in the language of languageCode (e.g.
+// "8 September 2025"), or else like [DateTime.String].
+func (d DateTime) Localized(languageCode string) string {
+	language := baseLanguage(languageCode)
+	if language == "" {
+		language = "en"
+	}
+
+	names, ok := monthNames[language]
+	if !ok {
+		return d.Format(d.Precision.layout(" ", true))
+	}
+	month := names[d.Month()-1]
+
+	var date string
+	switch language {
+	case "es":
+		date = fmt.Sprintf("%s de %d", month, d.Year())
+		if d.Precision <= PrecisionDay {
+			date = fmt.Sprintf("%d de %s", d.Day(), date)
+		}
+	case "fr":
+		date = fmt.Sprintf("%s %d", month, d.Year())
+		if d.Precision <= PrecisionDay {
+			day := strconv.Itoa(d.Day())
+			if d.Day() == 1 {
+				day = "1er"
+			}
+			date = day + " " + date
+		}
+	case "de":
+		date = fmt.Sprintf("%s %d", month, d.Year())
+		if d.Precision <= PrecisionDay {
+			date = fmt.Sprintf("%d. %s", d.Day(), date)
+		}
+	default:
+		date = fmt.Sprintf("%s %d", month, d.Year())
+		if d.Precision <= PrecisionDay {
+			date = fmt.Sprintf("%d %s", d.Day(), date)
+		}
+	}
+
+	switch d.Precision {
+	case PrecisionYear:
+		return strconv.Itoa(d.Year())
+	case PrecisionMinute:
+		return date + ", " + d.Format("15:04")
+	case PrecisionSecond:
+		return date + ", " + d.Format("15:04:05")
+	}
+
+	return date
+}
+
+var (
+	monthNames = map[string][]string{
+		"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	}
+)
+
 func dateFromString(input string) (DateTime, error) {
 	var errs error
 
 	for _, layout := range DateTimeLayouts {
 		t, err := time.Parse(layout, input)
 		if err == nil {
-			return DateTime{t}, nil
+			return DateTime{
+				Time:      t,
+				Precision: layoutPrecision(layout),
+				floating:  !strings.HasSuffix(layout, "Z07:00"),
+			}, nil
 		}
 		errs = errors.Join(errs, err)
 	}
 
-	return DateTime{time.Time{}}, errs
+	return DateTime{Time: time.Time{}}, errs
+}
+
+func layoutPrecision(layout string) DateTimePrecision {
+	switch {
+	case strings.Contains(layout, "15:04:05"):
+		return PrecisionSecond
+	case strings.Contains(layout, "15:04"):
+		return PrecisionMinute
+	case strings.Contains(layout, "01-02"):
+		return PrecisionDay
+	case strings.Contains(layout, "-01"):
+		return PrecisionMonth
+	}
+
+	return PrecisionYear
 }
@@ -0,0 +1,143 @@
+package pub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	tests := []struct {
+		input     string
+		precision DateTimePrecision
+		floating  bool
+		want      string
+	}{
+		{"2025", PrecisionYear, true, "2025"},
+		{"2025-09", PrecisionMonth, true, "2025-09"},
+		{"2025-09-08", PrecisionDay, true, "2025-09-08"},
+		{"2025-09-08 14:30", PrecisionMinute, true, "2025-09-08T14:30"},
+		{"2025-09-08T14:30:15", PrecisionSecond, true, "2025-09-08T14:30:15"},
+		{"2025 Z", PrecisionYear, false, "2025TZ"},
+		{"2025-09-08 +02:00", PrecisionDay, false, "2025-09-08T+02:00"},
+		{"2025-09-08T14:30:15-04:00", PrecisionSecond, false, "2025-09-08T14:30:15-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var d DateTime
+			if err := d.UnmarshalText([]byte(tt.input)); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+			if d.Precision != tt.precision || d.IsFloating() != tt.floating {
+				t.Errorf("Precision, IsFloating() = %v, %t, want %v, %t", d.Precision, d.IsFloating(), tt.precision, tt.floating)
+			}
+
+			data, err := json.Marshal(d)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if got := string(data); got != `"`+tt.want+`"` {
+				t.Errorf("MarshalJSON() = %s, want %q", got, tt.want)
+			}
+
+			var decoded DateTime
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !decoded.Equal(d.Time) || decoded.Precision != d.Precision || decoded.IsFloating() != d.IsFloating() {
+				t.Errorf("UnmarshalJSON() = %v, want %v", decoded, d)
+			}
+
+			data, err = yaml.Marshal(d)
+			if err != nil {
+				t.Fatalf("MarshalYAML() error = %v", err)
+			}
+			decoded = DateTime{}
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("UnmarshalYAML(%s) error = %v", data, err)
+			}
+			if !decoded.Equal(d.Time) || decoded.Precision != d.Precision {
+				t.Errorf("UnmarshalYAML() = %v, want %v", decoded, d)
+			}
+		})
+	}
+}
+
+func TestDateTimeSetDefaultLocation(t *testing.T) {
+	loc := time.FixedZone("EDT", -4*60*60)
+	tests := []struct {
+		input    string
+		wantUnix int64
+		want     string
+	}{
+		{"2025-09-08", time.Date(2025, 9, 8, 0, 0, 0, 0, loc).Unix(), "2025-09-08 -04:00"},
+		{"2025-09-08 14:30", time.Date(2025, 9, 8, 14, 30, 0, 0, loc).Unix(), "2025-09-08 14:30-04:00"},
+		{"2025-09-08 14:30Z", time.Date(2025, 9, 8, 14, 30, 0, 0, time.UTC).Unix(), "2025-09-08 14:30Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var d DateTime
+			if err := d.UnmarshalText([]byte(tt.input)); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+
+			d.setDefaultLocation(loc)
+			if d.IsFloating() {
+				t.Errorf("IsFloating() = true after setDefaultLocation()")
+			}
+			if d.Unix() != tt.wantUnix {
+				t.Errorf("setDefaultLocation() = %v, want %v", d.Time, time.Unix(tt.wantUnix, 0).In(loc))
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// applying another zone does not move a date and time that already has one
+			d.setDefaultLocation(time.UTC)
+			if d.Unix() != tt.wantUnix {
+				t.Errorf("second setDefaultLocation() = %v, want %v", d.Time, time.Unix(tt.wantUnix, 0).In(loc))
+			}
+		})
+	}
+
+	var d *DateTime
+	d.setDefaultLocation(loc)
+}
+
+func TestDateTimeLocalized(t *testing.T) {
+	tests := []struct {
+		input        string
+		languageCode string
+		want         string
+	}{
+		{"2025", "en", "2025"},
+		{"2025-09", "en", "September 2025"},
+		{"2025-09-08", "en-CA", "8 September 2025"},
+		{"2025-09-08 14:30", "en", "8 September 2025, 14:30"},
+		{"2025-09-08 14:30:15", "", "8 September 2025, 14:30:15"},
+		{"2025-09", "es", "septiembre de 2025"},
+		{"2025-09-08", "es-MX", "8 de septiembre de 2025"},
+		{"2025-08-01", "fr", "1er août 2025"},
+		{"2025-08-02", "fr", "2 août 2025"},
+		{"2025-03-08", "de", "8. März 2025"},
+		{"2025-09-08", "ja", "2025-09-08"},
+		{"2025-09-08 14:30Z", "ja", "2025-09-08 14:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+" "+tt.languageCode, func(t *testing.T) {
+			var d DateTime
+			if err := d.UnmarshalText([]byte(tt.input)); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+
+			if got := d.Localized(tt.languageCode); got != tt.want {
+				t.Errorf("Localized(%q) = %q, want %q", tt.languageCode, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package pub
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// Diagnostic is an error or warning found while validating a [Book], and where.
+// Line and Column are 0 when the position is unknown.
+type Diagnostic struct {
+	Severity Severity
+	FilePath string
+	Line     int
+	Column   int
+	Err      error
+}
+
+func (d Diagnostic) Error() string {
+	var pos string
+	switch {
+	case d.FilePath == "":
+	case d.Line == 0:
+		pos = d.FilePath + ": "
+	default:
+		pos = fmt.Sprintf("%s:%d:%d: ", d.FilePath, d.Line, d.Column)
+	}
+
+	return fmt.Sprintf("%s%s: %s", pos, d.Severity, d.Err)
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics is every [Diagnostic] found while validating a [Book], as an error.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	if len(d) == 1 {
+		return d[0].Error()
+	}
+
+	lines := []string{fmt.Sprintf("%d problems found:", len(d))}
+	for _, diagnostic := range d {
+		lines = append(lines, "  "+diagnostic.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (d Diagnostics) Unwrap() []error {
+	errs := make([]error, len(d))
+	for i := range d {
+		errs[i] = d[i]
+	}
+
+	return errs
+}
+
+func (d Diagnostics) Errors() Diagnostics {
+	return d.filter(SeverityError)
+}
+
+func (d Diagnostics) Warnings() Diagnostics {
+	return d.filter(SeverityWarning)
+}
+
+func (d Diagnostics) HasErrors() bool {
+	return len(d.Errors()) > 0
+}
+
+func (d Diagnostics) filter(severity Severity) Diagnostics {
+	var filtered Diagnostics
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			filtered = append(filtered, diagnostic)
+		}
+	}
+
+	return filtered
+}
+
+// yamlSource is a YAML document of type schema that a [Book] was decoded from.
+// lineOffset is the line it starts at in its file, minus 1.
+type yamlSource struct {
+	raw        []byte
+	lineOffset int
+	schema     reflect.Type
+
+	file *ast.File
+}
+
+func (s *yamlSource) parsed() (*ast.File, error) {
+	if s.file == nil {
+		f, err := parser.ParseBytes(s.raw, 0)
+		if err != nil {
+			return nil, err
+		}
+		s.file = f
+	}
+
+	return s.file, nil
+}
+
+// location points to a value by its YAML path (e.g. "$.authors[0]") in one or
+// more YAML sources, the first of which that contains it is used.
+type location []sourcePath
+
+type sourcePath struct {
+	filePath string
+	yamlPath string
+}
+
+func at(filePath, yamlPath string) location {
+	return location{{filePath: filePath, yamlPath: yamlPath}}
+}
+
+func (l location) field(name string) location {
+	return l.join("." + name)
+}
+
+func (l location) index(name string, i int) location {
+	return l.join(fmt.Sprintf(".%s[%d]", name, i))
+}
+
+func (l location) join(suffix string) location {
+	joined := make(location, len(l))
+	for i, p := range l {
+		joined[i] = sourcePath{filePath: p.filePath, yamlPath: p.yamlPath + suffix}
+	}
+
+	return joined
+}
+
+// validator collects every [Diagnostic] found while validating a [Book].
+type validator struct {
+	sources     map[string]*yamlSource
+	diagnostics Diagnostics
+}
+
+func (v *validator) error(loc location, err error) {
+	v.add(SeverityError, loc, err)
+}
+
+func (v *validator) warning(loc location, err error) {
+	v.add(SeverityWarning, loc, err)
+}
+
+// check records err (if it is not nil) as an error at loc, and reports whether err was nil.
+func (v *validator) check(loc location, err error) bool {
+	if err == nil {
+		return true
+	}
+	v.error(loc, err)
+
+	return false
+}
+
+func (v *validator) add(severity Severity, loc location, err error) {
+	filePath, line, column := v.position(loc)
+	v.addAt(severity, filePath, line, column, err)
+}
+
+func (v *validator) addAt(severity Severity, filePath string, line, column int, err error) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		FilePath: filePath,
+		Line:     line,
+		Column:   column,
+		Err:      err,
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.diagnostics.Errors()) == 0 {
+		return nil
+	}
+
+	return v.diagnostics
+}
+
+// position resolves loc in the first candidate that contains its value, or else
+// to its closest existing parent (e.g. for a missing field).
+func (v *validator) position(loc location) (filePath string, line, column int) {
+	if len(loc) == 0 {
+		return "", 0, 0
+	}
+
+	for _, p := range loc {
+		if line, column, ok := v.lookup(p.filePath, p.yamlPath); ok {
+			return p.filePath, line, column
+		}
+	}
+
+	filePath, depth := loc[0].filePath, -1
+	for _, p := range loc {
+		for yamlPath := parentYAMLPath(p.yamlPath); len(yamlPath) > depth; yamlPath = parentYAMLPath(yamlPath) {
+			if l, c, ok := v.lookup(p.filePath, yamlPath); ok {
+				filePath, line, column, depth = p.filePath, l, c, len(yamlPath)
+				break
+			}
+
+			if yamlPath == "$" {
+				break
+			}
+		}
+	}
+
+	return filePath, line, column
+}
+
+func (v *validator) lookup(filePath, yamlPath string) (line, column int, ok bool) {
+	source, ok := v.sources[filePath]
+	if !ok {
+		return 0, 0, false
+	}
+
+	f, err := source.parsed()
+	if err != nil {
+		return 0, 0, false
+	}
+
+	p, err := yaml.PathString(yamlPath)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	node, err := p.FilterFile(f)
+	if err != nil || node == nil {
+		return 0, 0, false
+	}
+
+	// point mappings to their first key rather than the ":" token
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			node = n.Values[0].Key
+		}
+	case *ast.MappingValueNode:
+		node = n.Key
+	}
+
+	token := node.GetToken()
+	if token == nil || token.Position == nil {
+		return 0, 0, false
+	}
+
+	return token.Position.Line + source.lineOffset, token.Position.Column, true
+}
+
+func parentYAMLPath(yamlPath string) string {
+	i := strings.LastIndexAny(yamlPath, ".[")
+	if i <= 1 {
+		return "$"
+	}
+
+	return yamlPath[:i]
+}
+
+// validateWith runs validate without YAML sources, returning any errors.
+func validateWith(validate func(v *validator)) error {
+	v := validator{}
+	validate(&v)
+
+	return v.err()
+}
@@ -0,0 +1,115 @@
+package pub
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDiagnosticError(t *testing.T) {
+	err := errors.New("missing Title")
+	tests := []struct {
+		name       string
+		diagnostic Diagnostic
+		want       string
+	}{
+		{"no file", Diagnostic{Severity: SeverityError, Err: err}, "error: missing Title"},
+		{"no line", Diagnostic{Severity: SeverityWarning, FilePath: "pub.yml", Err: err}, "pub.yml: warning: missing Title"},
+		{"position", Diagnostic{Severity: SeverityError, FilePath: "pub.yml", Line: 3, Column: 5, Err: err}, "pub.yml:3:5: error: missing Title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParentYAMLPath(t *testing.T) {
+	tests := []struct {
+		yamlPath string
+		want     string
+	}{
+		{"$", "$"},
+		{"$.title", "$"},
+		{"$.authors[0]", "$.authors"},
+		{"$.authors[0].name", "$.authors[0]"},
+		{"$[1].chapters[0]", "$[1].chapters"},
+	}
+
+	for _, tt := range tests {
+		if got := parentYAMLPath(tt.yamlPath); got != tt.want {
+			t.Errorf("parentYAMLPath(%q) = %q, want %q", tt.yamlPath, got, tt.want)
+		}
+	}
+}
+
+func TestNewBookFSDiagnostics(t *testing.T) {
+	type position struct {
+		filePath     string
+		line, column int
+	}
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want []position
+	}{
+		{"every error is collected", fstest.MapFS{
+			"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nauthors:\n  - email: a@example.com\n  - name: B\n  - email: c@example.com\n")},
+		}, []position{
+			{"book/pub.yml", 1, 1},
+			{"book/pub.yml", 4, 5},
+			{"book/pub.yml", 6, 5},
+		}},
+		{"nav.yml and front matter", fstest.MapFS{
+			"book/pub.yml":         {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+			"book/nav.yml":         {Data: []byte("- content_file_name: one.md\n  authors:\n    - email: a@example.com\n- content_file_name: two.md\n")},
+			"book/chapters/one.md": {Data: []byte("One\n")},
+			"book/chapters/two.md": {Data: []byte("---\nauthors:\n  - email: b@example.com\n---\nTwo\n")},
+		}, []position{
+			{"book/nav.yml", 3, 7},
+			{"book/chapters/two.md", 3, 5},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(tt.fsys, "book")
+
+			var diagnostics Diagnostics
+			if !errors.As(err, &diagnostics) {
+				t.Fatalf("NewBookFS() error = %v, want Diagnostics", err)
+			}
+
+			var got []position
+			for _, d := range diagnostics.Errors() {
+				got = append(got, position{d.FilePath, d.Line, d.Column})
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want positions %v", diagnostics.Errors(), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("errors[%d] at %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnosticsErrorsIs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nauthors:\n  - email: a@example.com\n")},
+	}
+
+	_, err := NewBookFS(fsys, "book")
+	for _, want := range []error{ErrBookMissingLanguageCode, ErrProfileMissingName} {
+		if !errors.Is(err, want) {
+			t.Errorf("errors.Is(%v, %v) = false, want true", err, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package pub
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+)
+
+const (
+	FrontMatterDelimiter = "---"
+)
+
+// splitFrontMatter separates the "---" delimited YAML block at the start of raw
+// from the body. ok is false if there is none.
+func splitFrontMatter(raw []byte) (frontMatter, body []byte, ok bool) {
+	rest := bytes.TrimPrefix(raw, []byte("\ufeff"))
+
+	line, rest, found := bytes.Cut(rest, []byte("\n"))
+	if !found || !isFrontMatterDelimiter(line) {
+		return nil, raw, false
+	}
+
+	start := len(raw) - len(rest)
+	for len(rest) > 0 {
+		end := len(raw) - len(rest)
+
+		line, after, _ := bytes.Cut(rest, []byte("\n"))
+		if isFrontMatterDelimiter(line) || string(bytes.TrimRight(line, " \t\r")) == "..." {
+			return raw[start:end], after, true
+		}
+
+		rest = after
+	}
+
+	return nil, raw, false
+}
+
+// readFrontMatter reads the file name of fsys up to the end of its front matter.
+func readFrontMatter(fsys fs.FS, name string) (head []byte, err error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		head = append(head, line...)
+
+		trimmed := bytes.TrimSuffix(line, []byte("\n"))
+		switch {
+		case len(head) == len(line):
+			if !isFrontMatterDelimiter(bytes.TrimPrefix(trimmed, []byte("\ufeff"))) {
+				return nil, nil
+			}
+		case isFrontMatterDelimiter(trimmed) || string(bytes.TrimRight(trimmed, " \t\r")) == "...":
+			return head, nil
+		}
+
+		if errors.Is(err, io.EOF) {
+			// an unterminated block is not front matter
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isFrontMatterDelimiter(line []byte) bool {
+	return string(bytes.TrimRight(line, " \t\r")) == FrontMatterDelimiter
+}
+
+// mergeExtra returns a map containing every key of base, overwritten by every key of override.
+func mergeExtra(base, override map[string]any) map[string]any {
+	if base == nil {
+		return override
+	}
+
+	merged := maps.Clone(base)
+	maps.Copy(merged, override)
+
+	return merged
+}
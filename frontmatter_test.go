@@ -0,0 +1,37 @@
+package pub
+
+import (
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		frontMatter string
+		body        string
+		ok          bool
+	}{
+		{"no front matter", "# Title\n", "", "# Title\n", false},
+		{"empty", "", "", "", false},
+		{"front matter", "---\ntitle: A\n---\n# Title\n", "title: A\n", "# Title\n", true},
+		{"empty front matter", "---\n---\nbody", "", "body", true},
+		{"dots end", "---\ntitle: A\n...\nbody", "title: A\n", "body", true},
+		{"byte order mark", "\ufeff---\ntitle: A\n---\nbody", "title: A\n", "body", true},
+		{"crlf", "---\r\ntitle: A\r\n---\r\nbody", "title: A\r\n", "body", true},
+		{"trailing spaces", "--- \ntitle: A\n---  \nbody", "title: A\n", "body", true},
+		{"no body", "---\ntitle: A\n---", "title: A\n", "", true},
+		{"unterminated", "---\ntitle: A\n", "", "---\ntitle: A\n", false},
+		{"not first line", "\n---\ntitle: A\n---\n", "", "\n---\ntitle: A\n---\n", false},
+		{"thematic break", "----\ntitle: A\n----\n", "", "----\ntitle: A\n----\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body, ok := splitFrontMatter([]byte(tt.raw))
+			if string(frontMatter) != tt.frontMatter || string(body) != tt.body || ok != tt.ok {
+				t.Errorf("splitFrontMatter(%q) = %q, %q, %v; want %q, %q, %v", tt.raw, frontMatter, body, ok, tt.frontMatter, tt.body, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package pub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+// Identifier schemes recognized in [Identifiers]. Others are not validated.
+const (
+	IdentifierISBN10 = "isbn-10"
+	IdentifierISBN13 = "isbn-13"
+	IdentifierISSN   = "issn"
+	IdentifierDOI    = "doi"
+	IdentifierUUID   = "uuid"
+	IdentifierASIN   = "asin"
+)
+
+var (
+	ErrBookNotOnDisk = errors.New("book was not loaded from a directory on disk")
+)
+
+type ErrIdentifierInvalid struct {
+	Key    string
+	Value  string
+	Reason string
+}
+
+func (e ErrIdentifierInvalid) Error() string {
+	return fmt.Sprintf("ids: \"%s\" value \"%s\" is invalid (%s)", e.Key, e.Value, e.Reason)
+}
+
+// Identifiers maps schemes (e.g. "isbn-13") to the identifiers of a [Book] or [Chapter].
+type Identifiers map[string]string
+
+// UnmarshalYAML decodes each identifier as written, even if it looks like a number.
+func (ids *Identifiers) UnmarshalYAML(node ast.Node) error {
+	if _, ok := node.(*ast.NullNode); ok {
+		*ids = nil
+		return nil
+	}
+
+	entries := mappingEntries(node)
+	if entries == nil {
+		return fmt.Errorf("ids: expected a mapping of identifier schemes to identifiers, not %s", node.Type())
+	}
+
+	*ids = make(Identifiers, len(entries))
+	for _, entry := range entries {
+		key := entry.Key.GetToken().Value
+		switch n := entry.Value.(type) {
+		case *ast.NullNode:
+			(*ids)[key] = ""
+		case *ast.StringNode:
+			(*ids)[key] = n.Value
+		case *ast.LiteralNode:
+			(*ids)[key] = n.Value.Value
+		case ast.ScalarNode:
+			(*ids)[key] = n.GetToken().Value
+		default:
+			return fmt.Errorf("ids: \"%s\" must be a single value, not %s", key, entry.Value.Type())
+		}
+	}
+
+	return nil
+}
+
+// normalize rewrites valid identifiers in their canonical form, and fills in the
+// ISBN-13 from the ISBN-10.
+func (ids Identifiers) normalize() {
+	for key, value := range ids {
+		if normalized, err := NormalizeIdentifier(key, value); err == nil {
+			ids[key] = normalized
+		}
+	}
+
+	isbn13Key := ids.key(IdentifierISBN13)
+	if isbn10, ok := ids[ids.key(IdentifierISBN10)]; ok && ids[isbn13Key] == "" {
+		if isbn13, err := ISBN10To13(isbn10); err == nil {
+			ids[isbn13Key] = isbn13
+		}
+	}
+}
+
+func (ids Identifiers) validate(v *validator, loc location) {
+	for _, key := range slices.Sorted(maps.Keys(ids)) {
+		_, err := NormalizeIdentifier(key, ids[key])
+		v.check(loc.field(key), err)
+	}
+}
+
+// key returns the key of the identifier in scheme in any case, or scheme.
+func (ids Identifiers) key(scheme string) string {
+	if _, ok := ids[scheme]; ok {
+		return scheme
+	}
+
+	for key := range ids {
+		if strings.EqualFold(key, scheme) {
+			return key
+		}
+	}
+
+	return scheme
+}
+
+// NormalizeIdentifier checks value as an identifier in the scheme named key, and
+// returns it in its canonical form.
+func NormalizeIdentifier(key, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	invalid := func(reason string) error {
+		return ErrIdentifierInvalid{Key: key, Value: value, Reason: reason}
+	}
+
+	switch strings.ToLower(key) {
+	case IdentifierISBN10:
+		isbn := compactIdentifier(value)
+		if !isValidISBN10(isbn) {
+			return "", invalid("must be 10 digits (the last may be X) with a valid check digit")
+		}
+		return isbn, nil
+	case IdentifierISBN13:
+		isbn := compactIdentifier(value)
+		if !isValidISBN13(isbn) {
+			return "", invalid("must be 13 digits starting with 978 or 979 with a valid check digit")
+		}
+		return isbn, nil
+	case IdentifierISSN:
+		issn := compactIdentifier(value)
+		if len(issn) != 8 || !isDigits(issn[:7]) || issnCheckDigit(issn[:7]) != issn[7] {
+			return "", invalid("must be 8 digits (the last may be X) with a valid check digit")
+		}
+		return issn[:4] + "-" + issn[4:], nil
+	case IdentifierDOI:
+		doi := value
+		for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+			if len(doi) >= len(prefix) && strings.EqualFold(doi[:len(prefix)], prefix) {
+				doi = doi[len(prefix):]
+				break
+			}
+		}
+		if !doiPattern.MatchString(doi) {
+			return "", invalid("must start with \"10.\", followed by a registrant code, \"/\" and a suffix")
+		}
+		return doi, nil
+	case IdentifierUUID:
+		uuid := strings.ToLower(value)
+		uuid = strings.TrimPrefix(uuid, "urn:uuid:")
+		uuid = strings.TrimSuffix(strings.TrimPrefix(uuid, "{"), "}")
+		if !uuidPattern.MatchString(uuid) {
+			return "", invalid("must be 32 hexadecimal digits in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+		}
+		return uuid, nil
+	case IdentifierASIN:
+		asin := strings.ToUpper(value)
+		if !asinPattern.MatchString(asin) || (isDigits(asin[:9]) && !isValidISBN10(asin)) {
+			return "", invalid("must be 10 letters or digits; an ASIN of digits must be a valid ISBN-10")
+		}
+		return asin, nil
+	}
+
+	return value, nil
+}
+
+// ISBN10To13 converts a valid ISBN-10 to its ISBN-13 (with the "978" prefix).
+func ISBN10To13(isbn10 string) (string, error) {
+	isbn := compactIdentifier(isbn10)
+	if !isValidISBN10(isbn) {
+		return "", ErrIdentifierInvalid{Key: IdentifierISBN10, Value: isbn10, Reason: "not a valid ISBN-10"}
+	}
+
+	isbn = "978" + isbn[:9]
+	return isbn + string(isbn13CheckDigit(isbn)), nil
+}
+
+// ISBN13To10 converts a valid ISBN-13 with the "978" prefix to its ISBN-10.
+func ISBN13To10(isbn13 string) (string, error) {
+	isbn := compactIdentifier(isbn13)
+	if !isValidISBN13(isbn) || !strings.HasPrefix(isbn, "978") {
+		return "", ErrIdentifierInvalid{Key: IdentifierISBN13, Value: isbn13, Reason: "not a valid ISBN-13 starting with 978"}
+	}
+
+	isbn = isbn[3:12]
+	return isbn + string(isbn10CheckDigit(isbn)), nil
+}
+
+// NewUUID returns a random (version 4) UUID.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+var (
+	doiPattern  = regexp.MustCompile(`^10\.\d{4,9}(\.\d+)*/\S+$`)
+	uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	asinPattern = regexp.MustCompile(`^[0-9A-Z]{10}$`)
+)
+
+// compactIdentifier removes hyphens and spaces from an ISBN or ISSN.
+func compactIdentifier(s string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func isValidISBN10(isbn string) bool {
+	return len(isbn) == 10 && isDigits(isbn[:9]) && isbn10CheckDigit(isbn[:9]) == isbn[9]
+}
+
+func isValidISBN13(isbn string) bool {
+	return len(isbn) == 13 && isDigits(isbn) && (strings.HasPrefix(isbn, "978") || strings.HasPrefix(isbn, "979")) && isbn13CheckDigit(isbn[:12]) == isbn[12]
+}
+
+func isbn10CheckDigit(digits string) byte {
+	sum := 0
+	for i := range 9 {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+
+	return checkDigitMod11((11 - sum%11) % 11)
+}
+
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i := range 12 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func issnCheckDigit(digits string) byte {
+	sum := 0
+	for i := range 7 {
+		sum += int(digits[i]-'0') * (8 - i)
+	}
+
+	return checkDigitMod11((11 - sum%11) % 11)
+}
+
+func checkDigitMod11(check int) byte {
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// UUID returns the book's "uuid" identifier. See [Book.PersistGeneratedUUID].
+func (b Book) UUID() string {
+	return b.IDs[b.IDs.key(IdentifierUUID)]
+}
+
+// HasGeneratedUUID reports whether the book's UUID was generated when loaded.
+func (b Book) HasGeneratedUUID() bool {
+	return b.generatedUUID
+}
+
+// ensureUUID generates a UUID for the book if it has none.
+func (b *Book) ensureUUID() {
+	if strings.TrimSpace(b.UUID()) != "" {
+		return
+	}
+
+	if b.IDs == nil {
+		b.IDs = make(Identifiers)
+	}
+	b.IDs[b.IDs.key(IdentifierUUID)] = NewUUID()
+	b.generatedUUID = true
+}
+
+// PersistGeneratedUUID writes the book's generated UUID to the "ids" of its
+// pub.yml, leaving the rest of the file untouched.
+func (b *Book) PersistGeneratedUUID() error {
+	if !b.generatedUUID {
+		return nil
+	}
+
+	if b.options.baseDir == "" {
+		return ErrBookNotOnDisk
+	}
+
+	configPath := filepath.Join(b.options.baseDir, filepath.FromSlash(path.Join(b.root, BookConfigFileName)))
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	updated, err := insertIdentifier(raw, IdentifierUUID, b.UUID())
+	if err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	if err := os.WriteFile(configPath, updated, info.Mode()&fs.ModePerm); err != nil {
+		return err
+	}
+	b.generatedUUID = false
+
+	return nil
+}
+
+// insertIdentifier adds "key: value" to the "ids" mapping of the YAML document raw.
+func insertIdentifier(raw []byte, key, value string) ([]byte, error) {
+	entry := key + ": " + strconv.Quote(value)
+
+	f, err := parser.ParseBytes(raw, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := yaml.PathString("$.ids")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bytes.SplitAfter(raw, []byte("\n"))
+	insertAt := func(line int, text string) []byte {
+		var b bytes.Buffer
+		for _, l := range lines[:line] {
+			b.Write(l)
+		}
+		if line > 0 && len(lines[line-1]) > 0 && !bytes.HasSuffix(lines[line-1], []byte("\n")) {
+			b.WriteString("\n")
+		}
+		b.WriteString(text + "\n")
+		for _, l := range lines[line:] {
+			b.Write(l)
+		}
+
+		return b.Bytes()
+	}
+
+	node, err := p.FilterFile(f)
+	if err != nil || node == nil {
+		return insertAt(len(lines), "ids:\n  "+entry), nil
+	}
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle {
+			return nil, errors.New("cannot add to \"ids\" written in flow style ({...}); add the identifier manually")
+		}
+		last := n.Values[len(n.Values)-1].Key.GetToken()
+		indent := strings.Repeat(" ", n.Values[0].Key.GetToken().Position.Column-1)
+		return insertAt(entryEnd(last, lines), indent+entry), nil
+	case *ast.MappingValueNode:
+		key := n.Key.GetToken()
+		indent := strings.Repeat(" ", key.Position.Column-1)
+		return insertAt(entryEnd(key, lines), indent+entry), nil
+	case *ast.NullNode:
+		line := node.GetToken().Position.Line
+		return insertAt(line, "  "+entry), nil
+	}
+
+	return nil, errors.New("\"ids\" is not a mapping; add the identifier manually")
+}
+
+// entryEnd returns the number of lines up to the end of the value of the block
+// mapping entry with the given key, which may span several lines.
+func entryEnd(key *token.Token, lines [][]byte) int {
+	end := len(lines)
+	for tk := key.Next; tk != nil; tk = tk.Next {
+		// the content of a block scalar starts at column 0
+		blockScalar := tk.Prev != nil && (tk.Prev.Type == token.LiteralType || tk.Prev.Type == token.FoldedType)
+		if !blockScalar && tk.Position.Line != key.Position.Line && tk.Position.Column <= key.Position.Column {
+			end = tk.Position.Line - 1
+			break
+		}
+	}
+
+	for end > key.Position.Line && len(bytes.TrimSpace(lines[end-1])) == 0 {
+		end--
+	}
+
+	return end
+}
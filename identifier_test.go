@@ -0,0 +1,270 @@
+package pub
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       string
+		wantErr    bool
+	}{
+		{IdentifierISBN10, "0-306-40615-2", "0306406152", false},
+		{IdentifierISBN10, "0 8044 2957 x", "080442957X", false},
+		{IdentifierISBN10, "0-306-40615-3", "", true},
+		{IdentifierISBN10, "030640615", "", true},
+		{IdentifierISBN13, "978-0-306-40615-7", "9780306406157", false},
+		{IdentifierISBN13, "979-10-90636-07-1", "9791090636071", false},
+		{IdentifierISBN13, "978-0-306-40615-6", "", true},
+		{IdentifierISBN13, "977-0-306-40615-7", "", true},
+		{IdentifierISSN, "0317-8471", "0317-8471", false},
+		{IdentifierISSN, "2434561x", "2434-561X", false},
+		{IdentifierISSN, "0317-8472", "", true},
+		{IdentifierDOI, "https://doi.org/10.1000/xyz123", "10.1000/xyz123", false},
+		{IdentifierDOI, "doi:10.1000.10/abc", "10.1000.10/abc", false},
+		{IdentifierDOI, "11.1000/xyz", "", true},
+		{IdentifierUUID, "urn:uuid:{6BA7B810-9DAD-11D1-80B4-00C04FD430C8}", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", false},
+		{IdentifierUUID, "6ba7b810-9dad-11d1-80b4", "", true},
+		{IdentifierASIN, "b00abc1234", "B00ABC1234", false},
+		{IdentifierASIN, "0306406152", "0306406152", false},
+		{IdentifierASIN, "0306406153", "", true},
+		{"isbn-13", " 9780306406157 ", "9780306406157", false},
+		{"ISBN-13", "978-0-306-40615-7", "9780306406157", false},
+		{"custom", "  anything  ", "anything", false},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeIdentifier(tt.key, tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeIdentifier(%q, %q) = %q, %v; want %q (error: %v)", tt.key, tt.value, got, err, tt.want, tt.wantErr)
+		}
+
+		var invalid ErrIdentifierInvalid
+		if tt.wantErr && !errors.As(err, &invalid) {
+			t.Errorf("NormalizeIdentifier(%q, %q) error = %v, want an ErrIdentifierInvalid", tt.key, tt.value, err)
+		}
+	}
+}
+
+func TestISBNConversion(t *testing.T) {
+	tests := []struct {
+		isbn10, isbn13 string
+	}{
+		{"0306406152", "9780306406157"},
+		{"080442957X", "9780804429573"},
+	}
+
+	for _, tt := range tests {
+		if got, err := ISBN10To13(tt.isbn10); err != nil || got != tt.isbn13 {
+			t.Errorf("ISBN10To13(%q) = %q, %v; want %q", tt.isbn10, got, err, tt.isbn13)
+		}
+		if got, err := ISBN13To10(tt.isbn13); err != nil || got != tt.isbn10 {
+			t.Errorf("ISBN13To10(%q) = %q, %v; want %q", tt.isbn13, got, err, tt.isbn10)
+		}
+	}
+
+	if _, err := ISBN13To10("9791090636071"); err == nil {
+		t.Errorf("ISBN13To10 of a 979 ISBN-13: error = nil, want an error")
+	}
+}
+
+func TestIdentifiersUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    Identifiers
+		wantErr bool
+	}{
+		{"strings", "ids:\n  doi: \"10.1000/xyz\"\n  custom: abc\n", Identifiers{"doi": "10.1000/xyz", "custom": "abc"}, false},
+		{"leading zero", "ids:\n  isbn-10: 0306406152\n", Identifiers{"isbn-10": "0306406152"}, false},
+		{"octal", "ids:\n  custom: 0o17\n", Identifiers{"custom": "0o17"}, false},
+		{"float", "ids:\n  custom: 1.50\n", Identifiers{"custom": "1.50"}, false},
+		{"boolean", "ids:\n  custom: yes\n", Identifiers{"custom": "yes"}, false},
+		{"null value", "ids:\n  custom:\n", Identifiers{"custom": ""}, false},
+		{"literal", "ids:\n  custom: |-\n    abc\n", Identifiers{"custom": "abc"}, false},
+		{"flow", "ids: {isbn-13: 9780306406157}\n", Identifiers{"isbn-13": "9780306406157"}, false},
+		{"null", "ids:\n", nil, false},
+		{"sequence value", "ids:\n  custom: [a, b]\n", nil, true},
+		{"not a mapping", "ids: [a]\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				IDs Identifiers `json:"ids"`
+			}
+			err := yaml.Unmarshal([]byte(tt.yaml), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got.IDs) != len(tt.want) || (got.IDs == nil) != (tt.want == nil) {
+				t.Fatalf("IDs = %#v, want %#v", got.IDs, tt.want)
+			}
+			for key, value := range tt.want {
+				if got.IDs[key] != value {
+					t.Errorf("IDs[%q] = %q, want %q", key, got.IDs[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestIdentifiersNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  Identifiers
+		want Identifiers
+	}{
+		{"isbn-13 filled in", Identifiers{"isbn-10": "0-306-40615-2"}, Identifiers{"isbn-10": "0306406152", "isbn-13": "9780306406157"}},
+		{"uppercase isbn-10", Identifiers{"ISBN-10": "0-306-40615-2"}, Identifiers{"ISBN-10": "0306406152", "isbn-13": "9780306406157"}},
+		{"uppercase isbn-13 kept", Identifiers{"ISBN-10": "0306406152", "ISBN-13": "979-10-90636-07-1"}, Identifiers{"ISBN-10": "0306406152", "ISBN-13": "9791090636071"}},
+		{"empty isbn-13 filled in", Identifiers{"isbn-10": "0306406152", "Isbn-13": ""}, Identifiers{"isbn-10": "0306406152", "Isbn-13": "9780306406157"}},
+		{"invalid kept", Identifiers{"isbn-10": "0306406153"}, Identifiers{"isbn-10": "0306406153"}},
+		{"unknown scheme", Identifiers{"custom": " abc "}, Identifiers{"custom": "abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ids.normalize()
+			if len(tt.ids) != len(tt.want) {
+				t.Fatalf("normalize() = %v, want %v", tt.ids, tt.want)
+			}
+			for key, value := range tt.want {
+				if tt.ids[key] != value {
+					t.Errorf("normalize()[%q] = %q, want %q", key, tt.ids[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestBookUUID(t *testing.T) {
+	tests := []struct {
+		name          string
+		ids           Identifiers
+		want          string
+		wantGenerated bool
+	}{
+		{"lowercase key", Identifiers{"uuid": "c86aa0bf-7fc1-4001-bd35-60c75c1faa4f"}, "c86aa0bf-7fc1-4001-bd35-60c75c1faa4f", false},
+		{"uppercase key", Identifiers{"UUID": "c86aa0bf-7fc1-4001-bd35-60c75c1faa4f"}, "c86aa0bf-7fc1-4001-bd35-60c75c1faa4f", false},
+		{"missing", nil, "", true},
+		{"empty", Identifiers{"UUID": " "}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := Book{IDs: tt.ids}
+			book.ensureUUID()
+
+			if book.HasGeneratedUUID() != tt.wantGenerated {
+				t.Errorf("HasGeneratedUUID() = %t, want %t", book.HasGeneratedUUID(), tt.wantGenerated)
+			}
+			if tt.want != "" && book.UUID() != tt.want {
+				t.Errorf("UUID() = %q, want %q", book.UUID(), tt.want)
+			}
+			if tt.wantGenerated && !uuidPattern.MatchString(book.UUID()) {
+				t.Errorf("UUID() = %q, want a generated UUID", book.UUID())
+			}
+			if len(book.IDs) != max(len(tt.ids), 1) {
+				t.Errorf("IDs = %v, want the UUID under a single key", book.IDs)
+			}
+		})
+	}
+}
+
+func TestPersistGeneratedUUIDNotOnDisk(t *testing.T) {
+	fsys := fstest.MapFS{"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")}}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	if err := book.PersistGeneratedUUID(); !errors.Is(err, ErrBookNotOnDisk) {
+		t.Errorf("PersistGeneratedUUID() error = %v, want %v", err, ErrBookNotOnDisk)
+	}
+	if string(fsys["book/pub.yml"].Data) != "unique_id: a\ntitle: A\nlanguage_code: en\n" {
+		t.Errorf("pub.yml = %q, want it unchanged", fsys["book/pub.yml"].Data)
+	}
+}
+
+func TestInsertIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no ids",
+			raw:  "title: A\n",
+			want: "title: A\nids:\n  uuid: \"u\"\n",
+		},
+		{
+			name: "no trailing newline",
+			raw:  "title: A",
+			want: "title: A\nids:\n  uuid: \"u\"\n",
+		},
+		{
+			name: "after the last identifier",
+			raw:  "ids:\n  isbn-13: 9780306406157\n  doi: 10.1000/xyz\ntitle: A\n",
+			want: "ids:\n  isbn-13: 9780306406157\n  doi: 10.1000/xyz\n  uuid: \"u\"\ntitle: A\n",
+		},
+		{
+			name: "single identifier with its indentation",
+			raw:  "ids:\n    doi: 10.1000/xyz\ntitle: A\n",
+			want: "ids:\n    doi: 10.1000/xyz\n    uuid: \"u\"\ntitle: A\n",
+		},
+		{
+			name: "after a block scalar",
+			raw:  "ids:\n  custom: |\n    line one\n\n    line two\n\ntitle: A\n",
+			want: "ids:\n  custom: |\n    line one\n\n    line two\n  uuid: \"u\"\n\ntitle: A\n",
+		},
+		{
+			name: "after a multi-line flow value",
+			raw:  "ids:\n  custom: [a,\n    b]\ntitle: A\n",
+			want: "ids:\n  custom: [a,\n    b]\n  uuid: \"u\"\ntitle: A\n",
+		},
+		{
+			name: "keeps comments",
+			raw:  "# Book\nids: # identifiers\n  doi: 10.1000/xyz # the DOI\n\n# more\ntitle: A\n",
+			want: "# Book\nids: # identifiers\n  doi: 10.1000/xyz # the DOI\n  uuid: \"u\"\n\n# more\ntitle: A\n",
+		},
+		{
+			name: "empty ids",
+			raw:  "ids:\ntitle: A\n",
+			want: "ids:\n  uuid: \"u\"\ntitle: A\n",
+		},
+		{
+			name:    "flow ids",
+			raw:     "ids: {doi: 10.1000/xyz}\n",
+			wantErr: true,
+		},
+		{
+			name:    "ids not a mapping",
+			raw:     "ids: [a]\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := insertIdentifier([]byte(tt.raw), IdentifierUUID, "u")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("insertIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("insertIdentifier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Command schemadocs writes the doc comments of the types of the pub package to a
+// Go file, as descriptions of its JSON Schemas.
+//
+// It is run by "go generate" in the directory of the pub package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// docLink matches doc links (e.g. "[Book]" or "[encoding/json]"), but not the
+// index in "Styles[i]".
+var docLink = regexp.MustCompile(`\[(\*?(?:[A-Z][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*|[A-Za-z_][A-Za-z0-9_]*(?:[./][A-Za-z_][A-Za-z0-9_]*)+))\]`)
+
+func main() {
+	output := flag.String("o", "schema_docs.go", "file to write")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	names, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") || name == *output {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files = append(files, f)
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, "github.com/JessebotX/pub", doc.PreserveAST)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	docs := make(map[string]string)
+	for _, t := range pkg.Types {
+		if text := cleanDoc(t.Doc); text != "" {
+			docs[t.Name] = text
+		}
+
+		for _, spec := range t.Decl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			for _, field := range structType.Fields.List {
+				text := cleanDoc(field.Doc.Text())
+				if text == "" {
+					text = cleanDoc(field.Comment.Text())
+				}
+
+				for _, name := range field.Names {
+					if text != "" && name.IsExported() {
+						docs[typeSpec.Name.Name+"."+name.Name] = text
+					}
+				}
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by internal/schemadocs; DO NOT EDIT.\n\npackage pub\n\n")
+	b.WriteString("// schemaDocs holds the doc comment of each type (e.g. \"Book\") and struct field (e.g. \"Book.Title\") of the package.\n")
+	b.WriteString("var schemaDocs = map[string]string{\n")
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\t%s: %s,\n", strconv.Quote(key), strconv.Quote(docs[key]))
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, src, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// cleanDoc returns the doc comment text without doc link brackets or trailing space.
+func cleanDoc(text string) string {
+	return strings.TrimSpace(docLink.ReplaceAllString(text, "$1"))
+}
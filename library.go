@@ -0,0 +1,553 @@
+package pub
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	LibraryConfigFileName = "library.yml"
+
+	// Directories of the output of a [Library] that hold its listings of books.
+	LibraryTagsDirName    = "tags"
+	LibraryAuthorsDirName = "authors"
+	LibrarySeriesDirName  = "series"
+)
+
+var (
+	ErrLibraryMissingTitle = errors.New("missing Title")
+	ErrLibraryNoBooks      = errors.New("library has no books (list book directories under \"books\")")
+)
+
+type ErrLibraryBookNotFound struct {
+	Pattern string
+}
+
+func (e ErrLibraryBookNotFound) Error() string {
+	return fmt.Sprintf("books: no book directory (containing %s) matches \"%s\"", BookConfigFileName, e.Pattern)
+}
+
+type ErrLibraryDuplicateBookUniqueID struct {
+	UniqueID string
+	Paths    []string
+}
+
+func (e ErrLibraryDuplicateBookUniqueID) Error() string {
+	return fmt.Sprintf("books: unique ID \"%s\" is used by more than one book (%s)", e.UniqueID, strings.Join(e.Paths, ", "))
+}
+
+type ErrLibraryBookSlugUnsafe struct {
+	UniqueID string
+	Slug     string
+}
+
+func (e ErrLibraryBookSlugUnsafe) Error() string {
+	if e.Slug == "" || strings.HasPrefix(e.Slug, ".") {
+		return fmt.Sprintf("books: unique ID \"%s\" cannot name the book's output directory (it must contain a letter or digit and must not start with \".\")", e.UniqueID)
+	}
+
+	return fmt.Sprintf("books: unique ID \"%s\" cannot name the book's output directory, as \"%s\" is reserved for the library's pages (reserved names: %s)", e.UniqueID, e.Slug, strings.Join(LibraryListingDirNames, ", "))
+}
+
+type ErrLibraryBookSlugCollision struct {
+	Slug      string
+	UniqueIDs []string
+	Paths     []string
+}
+
+func (e ErrLibraryBookSlugCollision) Error() string {
+	return fmt.Sprintf("books: unique IDs \"%s\" would all be written to the output directory \"%s\" (%s)", strings.Join(e.UniqueIDs, "\", \""), e.Slug, strings.Join(e.Paths, ", "))
+}
+
+// LibraryListingDirNames are the output directories of the listings of a [Library].
+var LibraryListingDirNames = []string{LibraryTagsDirName, LibraryAuthorsDirName, LibrarySeriesDirName}
+
+// Library is a collection of [Book]s that are published together, described by
+// a library.yml. BookPaths may contain glob patterns (e.g. "books/*").
+//
+// The series of the library's books are defined in series.yml, or SeriesFile.
+type Library struct {
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	URL          string         `json:"url"`
+	LanguageCode string         `json:"language_code"`
+	Content      Content        `json:"content"`
+	BookPaths    []string       `json:"books"`
+	SeriesFile   string         `json:"series_file"`
+	Extra        map[string]any `json:"extra"`
+
+	Books     []*Book            `json:"-"`
+	Series    []SeriesDefinition `json:"-"`
+	InputPath string             `json:"-"`
+
+	loc         location
+	bookLocs    []location
+	seriesPath  string
+	sources     map[string]*yamlSource
+	options     bookOptions
+	diagnostics Diagnostics
+}
+
+// Listing is a named group of books of a [Library] (e.g. every book with a tag).
+type Listing struct {
+	Name  string
+	Slug  string
+	Books []*Book
+
+	// Series is the definition of the series listed, if any.
+	Series *SeriesDefinition
+}
+
+// NewLibrary loads the library at the directory inputPath. See [NewLibraryFS].
+func NewLibrary(inputPath string, opts ...BookOption) (Library, error) {
+	absPath, err := filepath.Abs(inputPath)
+	if err != nil {
+		return Library{}, fmt.Errorf("[LIBRARY] \"%s\": %w", inputPath, err)
+	}
+
+	opts = append([]BookOption{WithBaseDirectory(absPath)}, opts...)
+
+	return NewLibraryFS(os.DirFS(absPath), ".", opts...)
+}
+
+// NewLibraryFS loads the library at the directory root of fsys, and its books
+// with opts.
+func NewLibraryFS(fsys fs.FS, root string, opts ...BookOption) (Library, error) {
+	var library Library
+	for _, opt := range opts {
+		opt(&library.options)
+	}
+
+	configPath := path.Join(root, LibraryConfigFileName)
+	library.InputPath = inputPathIn(library.options.baseDir, root)
+	library.loc = at(inputPathIn(library.options.baseDir, configPath), "$")
+
+	data, err := fs.ReadFile(fsys, configPath)
+	if err != nil {
+		return library, fmt.Errorf("[LIBRARY] \"%s\": parsing \"%s\": %w", library.InputPath, LibraryConfigFileName, err)
+	}
+	library.sources = map[string]*yamlSource{
+		inputPathIn(library.options.baseDir, configPath): {raw: data, schema: reflect.TypeFor[Library]()},
+	}
+
+	if err := yaml.Unmarshal(data, &library); err != nil {
+		return library, fmt.Errorf("[LIBRARY] \"%s\": parsing \"%s\": %w", library.InputPath, LibraryConfigFileName, err)
+	}
+
+	if len(library.Content.Raw) == 0 {
+		raw, err := fs.ReadFile(fsys, path.Join(root, "index.md"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return library, fmt.Errorf("[LIBRARY] \"%s\": %w", library.InputPath, err)
+		}
+		library.Content.Raw = raw
+	}
+
+	if err := library.loadSeries(fsys, root); err != nil {
+		return library, fmt.Errorf("[LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	// resolve the directories of the books, keeping the order of library.yml
+	var bookDirs []string
+	var bookLocs []location
+	var notFound []int
+	for i, pattern := range library.BookPaths {
+		matches, err := fs.Glob(fsys, path.Join(root, filepath.ToSlash(pattern), BookConfigFileName))
+		if err != nil {
+			return library, fmt.Errorf("[LIBRARY] \"%s\": books: %w", library.InputPath, err)
+		}
+		slices.SortFunc(matches, naturalCompare)
+
+		if len(matches) == 0 {
+			notFound = append(notFound, i)
+		}
+
+		for _, match := range matches {
+			dir := path.Dir(match)
+			if slices.Contains(bookDirs, dir) {
+				continue
+			}
+			bookDirs = append(bookDirs, dir)
+			bookLocs = append(bookLocs, library.loc.index("books", i))
+		}
+	}
+	library.bookLocs = bookLocs
+
+	books := make([]*Book, len(bookDirs))
+	errs := make([]error, len(bookDirs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, dir := range bookDirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			book, err := NewBookFS(fsys, dir, append(slices.Clip(opts), withinLibrary())...)
+			books[i], errs[i] = &book, err
+		}()
+	}
+	wg.Wait()
+	library.Books = books
+
+	if err := errors.Join(errs...); err != nil {
+		return library, fmt.Errorf("[LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	v := validator{sources: library.sources}
+	for _, i := range notFound {
+		v.error(library.loc.index("books", i), ErrLibraryBookNotFound{Pattern: library.BookPaths[i]})
+	}
+	library.validate(&v)
+	library.resolveSeries(&v)
+	library.diagnostics = v.diagnostics
+
+	if err := v.err(); err != nil {
+		return library, fmt.Errorf("[LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	return library, nil
+}
+
+// withinLibrary marks a book as loaded as part of a [Library].
+func withinLibrary() BookOption {
+	return func(o *bookOptions) {
+		o.inLibrary = true
+	}
+}
+
+// loadSeries decodes the series definitions of the library from its series file.
+func (l *Library) loadSeries(fsys fs.FS, root string) error {
+	name := path.Clean(path.Join(root, filepath.ToSlash(cmp.Or(l.SeriesFile, SeriesConfigFileName))))
+
+	var data []byte
+	var err error
+	switch {
+	case fs.ValidPath(name):
+		data, err = fs.ReadFile(fsys, name)
+	case l.options.baseDir != "":
+		data, err = os.ReadFile(filepath.Join(l.options.baseDir, filepath.FromSlash(name)))
+	default:
+		err = ErrConfigOutsideBook
+	}
+
+	if errors.Is(err, fs.ErrNotExist) && l.SeriesFile == "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(name), err)
+	}
+
+	l.seriesPath = inputPathIn(l.options.baseDir, name)
+	l.sources[l.seriesPath] = &yamlSource{raw: data, schema: reflect.TypeFor[[]SeriesDefinition]()}
+
+	if err := yaml.Unmarshal(data, &l.Series); err != nil {
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(name), err)
+	}
+
+	return nil
+}
+
+// inputPathIn returns the path reported for name, in a file system at baseDir.
+func inputPathIn(baseDir, name string) string {
+	if baseDir == "" {
+		return name
+	}
+
+	return filepath.Join(baseDir, filepath.FromSlash(name))
+}
+
+func (l *Library) validate(v *validator) {
+	v.checkUnknownKeys(l.options.strict)
+
+	if l.Title == "" {
+		v.error(l.loc.field("title"), ErrLibraryMissingTitle)
+	}
+
+	if len(l.BookPaths) == 0 {
+		v.error(l.loc.field("books"), ErrLibraryNoBooks)
+	}
+
+	// every book is written to a directory named after its slug
+	seen := make(map[string][]int)
+	var order []string
+	for i, book := range l.Books {
+		slug := book.Slug()
+		if !isSafeSlug(slug) || slices.Contains(LibraryListingDirNames, slug) {
+			v.error(l.bookLocs[i], ErrLibraryBookSlugUnsafe{UniqueID: book.UniqueID, Slug: slug})
+			continue
+		}
+
+		if _, ok := seen[slug]; !ok {
+			order = append(order, slug)
+		}
+		seen[slug] = append(seen[slug], i)
+	}
+
+	for _, slug := range order {
+		indexes := seen[slug]
+		if len(indexes) < 2 {
+			continue
+		}
+
+		var uniqueIDs, paths []string
+		for _, i := range indexes {
+			uniqueIDs = append(uniqueIDs, l.Books[i].UniqueID)
+			paths = append(paths, l.Books[i].InputPath)
+		}
+
+		if len(slices.Compact(slices.Sorted(slices.Values(uniqueIDs)))) == 1 {
+			v.error(l.bookLocs[indexes[1]], ErrLibraryDuplicateBookUniqueID{UniqueID: uniqueIDs[0], Paths: paths})
+			continue
+		}
+		v.error(l.bookLocs[indexes[1]], ErrLibraryBookSlugCollision{Slug: slug, UniqueIDs: uniqueIDs, Paths: paths})
+	}
+}
+
+// resolveSeries validates the series definitions of the library, and resolves
+// the series of each book from them.
+func (l *Library) resolveSeries(v *validator) {
+	seriesLoc := at(l.seriesPath, "$")
+
+	ids := make(map[string]bool)
+	for i := range l.Series {
+		definition := &l.Series[i]
+		loc := seriesLoc.join(fmt.Sprintf("[%d]", i))
+
+		definition.validate(v, loc)
+		if definition.ID != "" && ids[definition.ID] {
+			v.error(loc.field("id"), ErrSeriesDuplicateID{ID: definition.ID})
+		}
+		ids[definition.ID] = true
+		definition.Slug = slugify(definition.ID)
+
+		for j := range definition.Volumes {
+			volume := &definition.Volumes[j]
+			volume.Book = l.BookByUniqueID(volume.UniqueID)
+			if volume.Book == nil && volume.UniqueID != "" {
+				v.error(loc.index("books", j), ErrSeriesBookNotFound{Series: definition.Title, UniqueID: volume.UniqueID})
+			}
+		}
+	}
+
+	for _, book := range l.Books {
+		// problems with a book's references are reported in the book's own files
+		bv := validator{sources: book.sources}
+
+		referenced := make(map[*SeriesDefinition]bool)
+		for i := range book.Series {
+			series := &book.Series[i]
+			loc := book.loc.index("series", i)
+
+			definition := l.seriesDefinition(series.ID, series.Title)
+			if definition == nil {
+				if series.ID != "" || len(l.Series) > 0 {
+					bv.error(loc, ErrSeriesUnknown{Name: cmp.Or(series.ID, series.Title)})
+				}
+				continue
+			}
+
+			j := definition.volumeIndex(book.UniqueID)
+			if j < 0 {
+				bv.error(loc, ErrSeriesBookNotListed{Series: definition.Title})
+				continue
+			}
+
+			if series.Number != 0 && series.Number != definition.Volumes[j].Number {
+				bv.warning(loc.field("number"), ErrSeriesNumberMismatch{Series: definition.Title, Number: series.Number, Expected: definition.Volumes[j].Number})
+			}
+
+			*series = definition.resolve(*series, j)
+			referenced[definition] = true
+		}
+
+		for i := range l.Series {
+			definition := &l.Series[i]
+			if j := definition.volumeIndex(book.UniqueID); j >= 0 && !referenced[definition] {
+				book.Series = append(book.Series, definition.resolve(Series{}, j))
+			}
+		}
+
+		v.diagnostics = append(v.diagnostics, bv.diagnostics...)
+	}
+}
+
+// seriesDefinition returns the series defined with the given ID or title, or nil.
+func (l *Library) seriesDefinition(id, title string) *SeriesDefinition {
+	for i := range l.Series {
+		definition := &l.Series[i]
+		if (id != "" && definition.ID == id) || (id == "" && definition.Title == title) {
+			return definition
+		}
+	}
+
+	return nil
+}
+
+// Diagnostics returns the problems found in the library, followed by its books'.
+func (l Library) Diagnostics() Diagnostics {
+	diagnostics := slices.Clone(l.diagnostics)
+	for _, book := range l.Books {
+		diagnostics = append(diagnostics, book.Diagnostics()...)
+	}
+
+	return diagnostics
+}
+
+// BookByUniqueID returns the book with the given unique ID, or nil.
+func (l Library) BookByUniqueID(uniqueID string) *Book {
+	for _, book := range l.Books {
+		if book.UniqueID == uniqueID {
+			return book
+		}
+	}
+
+	return nil
+}
+
+// TagListings returns a listing of the books with each tag used in the library, sorted by tag.
+func (l Library) TagListings() []Listing {
+	return l.listings(func(book *Book) []string {
+		return book.Tags
+	})
+}
+
+// AuthorListings returns a listing of the books by each author, sorted by name.
+func (l Library) AuthorListings() []Listing {
+	names := make(map[string]string)
+	listings := l.listings(func(book *Book) []string {
+		var keys []string
+		for _, author := range book.Authors {
+			key := cmp.Or(author.ID, author.Name)
+			names[key] = cmp.Or(author.Name, author.ID)
+			keys = append(keys, key)
+		}
+
+		return keys
+	})
+
+	for i := range listings {
+		listings[i].Name = names[listings[i].Name]
+	}
+	slices.SortStableFunc(listings, func(a, b Listing) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return listings
+}
+
+// SeriesListings returns a listing of the books of each series, in series order.
+func (l Library) SeriesListings() []Listing {
+	if len(l.Series) > 0 {
+		listings := make([]Listing, 0, len(l.Series))
+		for i := range l.Series {
+			definition := &l.Series[i]
+
+			listing := Listing{Name: definition.Title, Slug: definition.Slug, Series: definition}
+			for _, volume := range definition.Volumes {
+				if volume.Book != nil {
+					listing.Books = append(listing.Books, volume.Book)
+				}
+			}
+			listings = append(listings, listing)
+		}
+		slices.SortFunc(listings, func(a, b Listing) int {
+			return naturalCompare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+
+		return listings
+	}
+
+	listings := l.listings(func(book *Book) []string {
+		var titles []string
+		for _, series := range book.Series {
+			titles = append(titles, series.Title)
+		}
+
+		return titles
+	})
+
+	for _, listing := range listings {
+		slices.SortStableFunc(listing.Books, func(a, b *Book) int {
+			return cmp.Compare(seriesNumber(a, listing.Name), seriesNumber(b, listing.Name))
+		})
+	}
+
+	return listings
+}
+
+func seriesNumber(book *Book, title string) float64 {
+	for _, series := range book.Series {
+		if series.Title == title {
+			return series.Number
+		}
+	}
+
+	return 0
+}
+
+// listings groups the books of the library by the keys returned by keysOf.
+func (l Library) listings(keysOf func(book *Book) []string) []Listing {
+	byKey := make(map[string]*Listing)
+	for _, book := range l.Books {
+		for _, key := range keysOf(book) {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+
+			listing, ok := byKey[key]
+			if !ok {
+				listing = &Listing{Name: key, Slug: slugify(key)}
+				byKey[key] = listing
+			}
+
+			if !slices.Contains(listing.Books, book) {
+				listing.Books = append(listing.Books, book)
+			}
+		}
+	}
+
+	listings := make([]Listing, 0, len(byKey))
+	for _, listing := range byKey {
+		listings = append(listings, *listing)
+	}
+	slices.SortFunc(listings, func(a, b Listing) int {
+		return cmp.Or(naturalCompare(strings.ToLower(a.Name), strings.ToLower(b.Name)), strings.Compare(a.Name, b.Name))
+	})
+
+	// keys may share a slug (e.g. "Sci-Fi" and "sci fi"), or take a numbered one
+	taken := make(map[string]bool, len(listings))
+	for _, listing := range listings {
+		taken[listing.Slug] = true
+	}
+
+	seen := make(map[string]bool, len(listings))
+	for i := range listings {
+		slug := listings[i].Slug
+		if seen[slug] {
+			n := 2
+			for taken[fmt.Sprintf("%s-%d", slug, n)] {
+				n++
+			}
+			listings[i].Slug = fmt.Sprintf("%s-%d", slug, n)
+			taken[listings[i].Slug] = true
+		}
+		seen[slug] = true
+	}
+
+	return listings
+}
@@ -0,0 +1,126 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// libraryFS returns a file system with a library at "lib" of a book per pubYMLs.
+func libraryFS(pubYMLs ...string) fstest.MapFS {
+	fsys := fstest.MapFS{
+		"lib/library.yml": {Data: []byte("title: Library\nbooks:\n  - books/*\n")},
+	}
+	for i, pubYML := range pubYMLs {
+		fsys["lib/books/"+string(rune('a'+i))+"/pub.yml"] = &fstest.MapFile{Data: []byte(pubYML)}
+	}
+
+	return fsys
+}
+
+func TestLibraryBookSlugs(t *testing.T) {
+	tests := []struct {
+		name    string
+		pubYMLs []string
+		want    error
+	}{
+		{"distinct", []string{"unique_id: one\nlanguage_code: en\n", "unique_id: two\nlanguage_code: en\n"}, nil},
+		{"collision", []string{"unique_id: My Book\nlanguage_code: en\n", "unique_id: my-book\nlanguage_code: en\n"}, ErrLibraryBookSlugCollision{}},
+		{"duplicate unique ID", []string{"unique_id: one\nlanguage_code: en\n", "unique_id: One\nlanguage_code: en\n"}, ErrLibraryDuplicateBookUniqueID{}},
+		{"reserved", []string{"unique_id: tags\nlanguage_code: en\n"}, ErrLibraryBookSlugUnsafe{}},
+		{"hidden", []string{"unique_id: .git\nlanguage_code: en\n"}, ErrLibraryBookSlugUnsafe{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLibraryFS(libraryFS(tt.pubYMLs...), "lib")
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("NewLibraryFS() error = %v", err)
+				}
+				return
+			}
+
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("NewLibraryFS() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLibraryTagListings(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  []string
+		names []string
+		slugs []string
+	}{
+		{"distinct", []string{"fantasy", "Sci-Fi"}, []string{"fantasy", "Sci-Fi"}, []string{"fantasy", "sci-fi"}},
+		{"same slug", []string{"Sci-Fi", "sci fi"}, []string{"sci fi", "Sci-Fi"}, []string{"sci-fi", "sci-fi-2"}},
+		{"numbered slug taken", []string{"Sci-Fi", "sci fi", "sci-fi-2"}, []string{"sci fi", "Sci-Fi", "sci-fi-2"}, []string{"sci-fi", "sci-fi-3", "sci-fi-2"}},
+		{"numbered slug taken twice", []string{"a", "A", "a 2", "a-2", "a-3"}, []string{"A", "a", "a 2", "a-2", "a-3"}, []string{"a", "a-4", "a-2", "a-2-2", "a-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pubYMLs []string
+			for i, tag := range tt.tags {
+				pubYMLs = append(pubYMLs, "unique_id: book-"+string(rune('a'+i))+"\nlanguage_code: en\ntags: ['"+tag+"']\n")
+			}
+
+			library, err := NewLibraryFS(libraryFS(pubYMLs...), "lib")
+			if err != nil {
+				t.Fatalf("NewLibraryFS() error = %v", err)
+			}
+
+			var names, slugs []string
+			for _, listing := range library.TagListings() {
+				names = append(names, listing.Name)
+				slugs = append(slugs, listing.Slug)
+			}
+			if !reflect.DeepEqual(names, tt.names) || !reflect.DeepEqual(slugs, tt.slugs) {
+				t.Errorf("TagListings() = %v %v, want %v %v", names, slugs, tt.names, tt.slugs)
+			}
+		})
+	}
+}
+
+func TestLibraryAuthorListings(t *testing.T) {
+	fsys := libraryFS(
+		"unique_id: a\nlanguage_code: en\nauthors:\n  - id: jane\n    name: Jane Doe\n",
+		"unique_id: b\nlanguage_code: en\nauthors:\n  - id: jane\n    name: Jane Doe\n  - name: Sam Roe\n",
+		"unique_id: c\nlanguage_code: en\nauthors:\n  - id: other-jane\n    name: Jane Doe\n",
+	)
+	fsys["lib/books/a/profiles.yml"] = &fstest.MapFile{Data: []byte("jane:\n  name: Jane Doe\n")}
+	fsys["lib/books/b/profiles.yml"] = &fstest.MapFile{Data: []byte("jane:\n  name: Jane Doe\n")}
+	fsys["lib/books/c/profiles.yml"] = &fstest.MapFile{Data: []byte("other-jane:\n  name: Jane Doe\n")}
+
+	library, err := NewLibraryFS(fsys, "lib")
+	if err != nil {
+		t.Fatalf("NewLibraryFS() error = %v", err)
+	}
+
+	type listing struct {
+		name  string
+		books []string
+	}
+	var got []listing
+	for _, l := range library.AuthorListings() {
+		var books []string
+		for _, book := range l.Books {
+			books = append(books, book.UniqueID)
+		}
+		got = append(got, listing{l.Name, books})
+	}
+
+	want := []listing{
+		{"Jane Doe", []string{"a", "b"}},
+		{"Jane Doe", []string{"c"}},
+		{"Sam Roe", []string{"b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuthorListings() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,299 @@
+package pub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+const (
+	// CurrentSchemaVersion is the version of pub.yml and nav.yml this pub understands.
+	CurrentSchemaVersion = 1
+	SchemaVersionKey     = "schema"
+)
+
+type ErrSchemaVersionUnsupported struct {
+	Version int
+}
+
+func (e ErrSchemaVersionUnsupported) Error() string {
+	if e.Version < 0 {
+		return fmt.Sprintf("schema: unrecognized version %d (value must be between 1 and %d)", e.Version, CurrentSchemaVersion)
+	}
+
+	return fmt.Sprintf("schema: version %d is newer than the latest version supported by this version of pub (%d); upgrade pub to build this book", e.Version, CurrentSchemaVersion)
+}
+
+type ErrSchemaVersionOutdated struct {
+	Version int
+}
+
+func (e ErrSchemaVersionOutdated) Error() string {
+	if e.Version == 0 {
+		return fmt.Sprintf("schema: no version is set (the current version is %d); run \"pub migrate\" to add it to pub.yml", CurrentSchemaVersion)
+	}
+
+	return fmt.Sprintf("schema: version %d is outdated (the current version is %d); run \"pub migrate\" to upgrade pub.yml and nav.yml", e.Version, CurrentSchemaVersion)
+}
+
+// schemaMigration returns the edits that upgrade pub.yml and nav.yml from one
+// schema version to the next, keeping comments and formatting.
+type schemaMigration struct {
+	description string
+	book        func(body ast.Node) []yamlEdit
+	chapters    func(body ast.Node) []yamlEdit
+}
+
+// schemaMigrations[i] upgrades schema version i to version i+1.
+var schemaMigrations = []schemaMigration{
+	// version 1 only adds the schema key, which [MigrateBook] sets once every migration is applied
+	{description: "add the schema version"},
+}
+
+// schemaVersionOf returns the schema version of the config document raw, or 0.
+func schemaVersionOf(raw []byte) (int, error) {
+	var header struct {
+		Schema int `json:"schema"`
+	}
+
+	if err := yaml.Unmarshal(raw, &header); err != nil {
+		return 0, err
+	}
+
+	if header.Schema < 0 || header.Schema > CurrentSchemaVersion {
+		return 0, ErrSchemaVersionUnsupported{Version: header.Schema}
+	}
+
+	return header.Schema, nil
+}
+
+// migrateYAML upgrades the YAML document raw of m from schema version to
+// [CurrentSchemaVersion], keeping the line of every value.
+func migrateYAML(raw []byte, version int, m any) ([]byte, error) {
+	for _, migration := range schemaMigrations[version:] {
+		var migrate func(ast.Node) []yamlEdit
+		switch m.(type) {
+		case *Book:
+			migrate = migration.book
+		case *[]Chapter:
+			migrate = migration.chapters
+		default:
+			return raw, nil
+		}
+
+		if migrate == nil {
+			continue
+		}
+
+		f, err := parser.ParseBytes(raw, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		var edits []yamlEdit
+		for _, doc := range f.Docs {
+			edits = append(edits, migrate(doc.Body)...)
+		}
+
+		raw, err = applyYAMLEdits(raw, edits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return raw, nil
+}
+
+// MigrateBook upgrades the book project at inputPath to [CurrentSchemaVersion],
+// returning the changed files. When dryRun is true, nothing is written.
+func MigrateBook(inputPath string, dryRun bool) ([]string, error) {
+	configPath := filepath.Join(inputPath, BookConfigFileName)
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := schemaVersionOf(config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	if version == CurrentSchemaVersion {
+		return nil, nil
+	}
+
+	migratedConfig, err := migrateYAML(config, version, &Book{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	migratedConfig, err = setSchemaVersion(migratedConfig, CurrentSchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	files := map[string][]byte{configPath: migratedConfig}
+
+	navPath := filepath.Join(inputPath, BookChaptersConfigFileName)
+	nav, err := os.ReadFile(navPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		migratedNav, err := migrateYAML(nav, version, &[]Chapter{})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", navPath, err)
+		}
+
+		if !bytes.Equal(nav, migratedNav) {
+			files[navPath] = migratedNav
+		}
+	}
+
+	changed := []string{configPath}
+	if _, ok := files[navPath]; ok {
+		changed = append(changed, navPath)
+	}
+
+	if dryRun {
+		return changed, nil
+	}
+
+	for _, filePath := range changed {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(filePath, files[filePath], info.Mode()&fs.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
+
+// setSchemaVersion sets the schema key of the pub.yml document raw to version.
+func setSchemaVersion(raw []byte, version int) ([]byte, error) {
+	f, err := parser.ParseBytes(raw, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return append([]byte(fmt.Sprintf("%s: %d\n", SchemaVersionKey, version)), raw...), nil
+	}
+	body := f.Docs[0].Body
+
+	if existing, ok := mappingValue(body, SchemaVersionKey).(ast.ScalarNode); ok {
+		return applyYAMLEdits(raw, []yamlEdit{replaceScalar(existing, strconv.Itoa(version))})
+	}
+
+	var first ast.Node = body
+	switch n := body.(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle || len(n.Values) == 0 {
+			return nil, errors.New("cannot add the schema key to a pub.yml that is not a block mapping")
+		}
+		first = n.Values[0].Key
+	case *ast.MappingValueNode:
+		first = n.Key
+	default:
+		return nil, errors.New("cannot add the schema key to a pub.yml that is not a mapping")
+	}
+
+	pos := first.GetToken().Position
+	return applyYAMLEdits(raw, []yamlEdit{{
+		line:   pos.Line,
+		column: pos.Column,
+		new:    fmt.Sprintf("%s: %d\n%s", SchemaVersionKey, version, strings.Repeat(" ", pos.Column-1)),
+	}})
+}
+
+// mappingValue returns the value of key in the mapping node, or nil.
+func mappingValue(node ast.Node, key string) ast.Node {
+	var values []*ast.MappingValueNode
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		values = n.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{n}
+	}
+
+	for _, value := range values {
+		if value.Key.GetToken().Value == key {
+			return value.Value
+		}
+	}
+
+	return nil
+}
+
+// yamlEdit replaces the text old at line and column of a YAML document with new.
+type yamlEdit struct {
+	line   int
+	column int
+	old    string
+	new    string
+}
+
+// replaceScalar returns the edit that replaces the scalar node with value.
+func replaceScalar(node ast.ScalarNode, value string) yamlEdit {
+	tk := node.GetToken()
+
+	edit := yamlEdit{line: tk.Position.Line, column: tk.Position.Column}
+	switch tk.Type {
+	case token.DoubleQuoteType:
+		edit.old, edit.new = strconv.Quote(tk.Value), strconv.Quote(value)
+	case token.SingleQuoteType:
+		edit.old = "'" + strings.ReplaceAll(tk.Value, "'", "''") + "'"
+		edit.new = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		edit.old, edit.new = tk.Value, value
+	}
+
+	return edit
+}
+
+// applyYAMLEdits makes every edit to raw, failing if its old text is not found.
+func applyYAMLEdits(raw []byte, edits []yamlEdit) ([]byte, error) {
+	lines := strings.SplitAfter(string(raw), "\n")
+
+	// apply later edits on the same line first, so that the columns of earlier edits stay valid
+	edits = slices.Clone(edits)
+	slices.SortFunc(edits, func(a, b yamlEdit) int {
+		if a.line != b.line {
+			return a.line - b.line
+		}
+		return b.column - a.column
+	})
+
+	for _, edit := range edits {
+		if edit.line < 1 || edit.line > len(lines) {
+			return nil, fmt.Errorf("line %d: out of range", edit.line)
+		}
+
+		line := []rune(lines[edit.line-1])
+		start := edit.column - 1
+		if start < 0 || start > len(line) || !strings.HasPrefix(string(line[start:]), edit.old) {
+			return nil, fmt.Errorf("line %d, column %d: expected \"%s\"", edit.line, edit.column, edit.old)
+		}
+
+		rest := string(line[start:])[len(edit.old):]
+		lines[edit.line-1] = string(line[:start]) + edit.new + rest
+	}
+
+	return []byte(strings.Join(lines, "")), nil
+}
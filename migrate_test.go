@@ -0,0 +1,197 @@
+package pub
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+// renameKeyMigration returns a migration that renames the key old to new.
+func renameKeyMigration(old, new string) schemaMigration {
+	rename := func(node ast.Node) []yamlEdit {
+		var edits []yamlEdit
+		for _, entry := range mappingEntries(node) {
+			if key, ok := entry.Key.(ast.ScalarNode); ok && entry.Key.GetToken().Value == old {
+				edits = append(edits, replaceScalar(key, new))
+			}
+		}
+
+		return edits
+	}
+
+	return schemaMigration{
+		description: "rename " + old + " to " + new,
+		book:        rename,
+		chapters: func(body ast.Node) []yamlEdit {
+			var edits []yamlEdit
+			if sequence, ok := body.(*ast.SequenceNode); ok {
+				for _, value := range sequence.Values {
+					edits = append(edits, rename(value)...)
+				}
+			}
+
+			return edits
+		},
+	}
+}
+
+func TestMigrateYAML(t *testing.T) {
+	migrations := schemaMigrations
+	t.Cleanup(func() { schemaMigrations = migrations })
+	schemaMigrations = []schemaMigration{
+		renameKeyMigration("name", "title"),
+		renameKeyMigration("title", "heading"),
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		version int
+		m       any
+		want    string
+	}{
+		{
+			name:    "book from the first version",
+			raw:     "# comment\nname: A # trailing\nsubtitle: B\n",
+			version: 0,
+			m:       &Book{},
+			want:    "# comment\nheading: A # trailing\nsubtitle: B\n",
+		},
+		{
+			name:    "book from a later version",
+			raw:     "name: A\ntitle: B\n",
+			version: 1,
+			m:       &Book{},
+			want:    "name: A\nheading: B\n",
+		},
+		{
+			name:    "quoted key",
+			raw:     "'name': A\n",
+			version: 0,
+			m:       &Book{},
+			want:    "'heading': A\n",
+		},
+		{
+			name:    "chapters",
+			raw:     "- name: A\n  chapters:\n    - name: B\n- content_file_name: c.md\n",
+			version: 0,
+			m:       &[]Chapter{},
+			want:    "- heading: A\n  chapters:\n    - name: B\n- content_file_name: c.md\n",
+		},
+		{
+			name:    "current version",
+			raw:     "name: A\n",
+			version: 2,
+			m:       &Book{},
+			want:    "name: A\n",
+		},
+		{
+			name:    "other documents",
+			raw:     "name: A\n",
+			version: 0,
+			m:       &map[string]Profile{},
+			want:    "name: A\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := migrateYAML([]byte(tt.raw), tt.version, tt.m)
+			if err != nil {
+				t.Fatalf("migrateYAML() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("migrateYAML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaVersionOf(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{"title: A\n", 0, false},
+		{"schema: 0\ntitle: A\n", 0, false},
+		{"schema: 1\ntitle: A\n", 1, false},
+		{"schema: -1\n", 0, true},
+		{"schema: 999\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := schemaVersionOf([]byte(tt.raw))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("schemaVersionOf(%q) = %d, %v; want %d (error: %v)", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMigrateBook(t *testing.T) {
+	tests := []struct {
+		name        string
+		pubYML      string
+		dryRun      bool
+		want        string
+		wantChanged bool
+	}{
+		{"unversioned", "# my book\nunique_id: a # id\ntitle: A\n", false, "# my book\nschema: 1\nunique_id: a # id\ntitle: A\n", true},
+		{"version 0", "schema: 0\nunique_id: a\n", false, "schema: 1\nunique_id: a\n", true},
+		{"dry run", "unique_id: a\n", true, "unique_id: a\n", true},
+		{"current", "schema: 1\nunique_id: a\n", false, "schema: 1\nunique_id: a\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, BookConfigFileName)
+			if err := os.WriteFile(configPath, []byte(tt.pubYML), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			changed, err := MigrateBook(dir, tt.dryRun)
+			if err != nil {
+				t.Fatalf("MigrateBook() error = %v", err)
+			}
+			if got := slices.Contains(changed, configPath); got != tt.wantChanged {
+				t.Errorf("MigrateBook() = %v, want pub.yml changed %t", changed, tt.wantChanged)
+			}
+
+			got, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("pub.yml = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaVersionWarning(t *testing.T) {
+	tests := []struct {
+		pubYML string
+		want   bool
+	}{
+		{"unique_id: a\ntitle: A\nlanguage_code: en\n", true},
+		{"schema: 1\nunique_id: a\ntitle: A\nlanguage_code: en\n", false},
+	}
+
+	for _, tt := range tests {
+		book, err := NewBookFS(fstest.MapFS{"book/pub.yml": {Data: []byte(tt.pubYML)}}, "book")
+		if err != nil {
+			t.Fatalf("NewBookFS() error = %v", err)
+		}
+
+		got := errors.Is(book.Diagnostics(), ErrSchemaVersionOutdated{Version: 0})
+		if got != tt.want {
+			t.Errorf("NewBookFS(%q) warns about the schema version: %t, want %t", tt.pubYML, got, tt.want)
+		}
+	}
+}
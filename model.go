@@ -0,0 +1,120 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Formats of the serialized book model, see [MarshalModel].
+const (
+	ModelFormatJSON = "json"
+	ModelFormatYAML = "yaml"
+)
+
+type ErrModelFormatUnknown struct {
+	Format string
+}
+
+func (e ErrModelFormatUnknown) Error() string {
+	return fmt.Sprintf("model format \"%s\" is not supported (value must be one of the following: %s, %s)", e.Format, ModelFormatJSON, ModelFormatYAML)
+}
+
+// bookModel is the layout of a book serialized by [MarshalModel], with the
+// fields that are not part of pub.yml.
+type bookModel struct {
+	Book                     `yaml:",inline"`
+	ReadingOrders            []ReadingOrder            `json:"reading_orders"`
+	Translations             []Book                    `json:"translations"`
+	TranslationReadingOrders map[string][]ReadingOrder `json:"translation_reading_orders"`
+}
+
+// MarshalModel serializes the fully resolved book in format, to be read back by
+// [UnmarshalModel].
+func MarshalModel(book *Book, format string) ([]byte, error) {
+	model := bookModel{Book: *book, ReadingOrders: book.ReadingOrders, Translations: book.Translations}
+	for _, translation := range book.Translations {
+		if len(translation.ReadingOrders) == 0 {
+			continue
+		}
+
+		if model.TranslationReadingOrders == nil {
+			model.TranslationReadingOrders = make(map[string][]ReadingOrder)
+		}
+		model.TranslationReadingOrders[translation.LanguageCode] = translation.ReadingOrders
+	}
+
+	switch strings.ToLower(format) {
+	case ModelFormatJSON:
+		data, err := json.MarshalIndent(model, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ModelFormatYAML:
+		return yaml.Marshal(model)
+	}
+
+	return nil, ErrModelFormatUnknown{Format: format}
+}
+
+// UnmarshalModel reads a book serialized by [MarshalModel] in format, and
+// validates it.
+func UnmarshalModel(data []byte, format string) (Book, error) {
+	var model bookModel
+
+	var err error
+	switch strings.ToLower(format) {
+	case ModelFormatJSON:
+		err = json.Unmarshal(data, &model)
+	case ModelFormatYAML:
+		err = yaml.Unmarshal(data, &model)
+	default:
+		err = ErrModelFormatUnknown{Format: format}
+	}
+
+	book := model.Book
+	if err != nil {
+		return book, fmt.Errorf("[BOOK] \"%s\": %w", book.UniqueID, err)
+	}
+
+	book.ReadingOrders = model.ReadingOrders
+	book.Translations = model.Translations
+	for i := range book.Translations {
+		book.Translations[i].ReadingOrders = model.TranslationReadingOrders[book.Translations[i].LanguageCode]
+	}
+
+	if err := book.linkModel(); err != nil {
+		return book, fmt.Errorf("[BOOK] \"%s\": %w", book.UniqueID, err)
+	}
+
+	for i := range book.Translations {
+		translation := &book.Translations[i]
+		if err := translation.linkModel(); err != nil {
+			return book, fmt.Errorf("[BOOK] \"%s\" (%s): %w", translation.UniqueID, translation.LanguageCode, err)
+		}
+	}
+	book.linkTranslations()
+
+	return book, nil
+}
+
+// linkModel restores the links of a book read by [UnmarshalModel], and validates it.
+func (b *Book) linkModel() error {
+	setChapterHierarchy(b.Chapters, nil)
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		chapter.Book = b
+	}
+	linkChapters(b.Chapters)
+	b.normalize()
+
+	if err := b.EnsureValid(); err != nil {
+		return err
+	}
+	b.linkReadingOrders()
+	b.linkTaxonomies()
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package pub
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestModelRoundTrip(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":                         {Data: []byte("unique_id: test-book\ntitle: Test Book\nlanguage_code: en\nids:\n  isbn-10: 0306406152\ntaxonomies:\n  - id: pov\nextra:\n  key: value\n")},
+		"book/nav.yml":                         {Data: []byte("- unique_id: one\n  content_file_name: one.md\n  taxonomies:\n    pov: [Mira]\n  chapters:\n    - unique_id: one-one\n      title: One.One\n- unique_id: appendix\n  title: Appendix\n  kind: appendix\n")},
+		"book/reading_orders.yml":              {Data: []byte("- id: reverse\n  title: Reverse\n  chapters: [appendix, one-one, one]\n")},
+		"book/chapters/one.md":                 {Data: []byte("---\ntitle: One\n---\nFirst.\n")},
+		"book/translations/es/pub.yml":         {Data: []byte("title: Libro\n")},
+		"book/translations/es/chapters/one.md": {Data: []byte("---\ntitle: Uno\n---\nPrimero.\n")},
+	}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+	book.Chapters[0].Content.AddFormat("html", "<p>First.</p>\n")
+
+	for _, format := range []string{ModelFormatJSON, ModelFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalModel(&book, format)
+			if err != nil {
+				t.Fatalf("MarshalModel() error = %v", err)
+			}
+
+			got, err := UnmarshalModel(data, format)
+			if err != nil {
+				t.Fatalf("UnmarshalModel() error = %v\n%s", err, data)
+			}
+
+			if got.UniqueID != book.UniqueID || got.Title != book.Title || got.UUID() != book.UUID() || got.IDs[IdentifierISBN13] != "9780306406157" || got.Extra["key"] != "value" {
+				t.Errorf("book = %q, %q, %v, %v; want %q, %q, %v, %v", got.UniqueID, got.Title, got.IDs, got.Extra, book.UniqueID, book.Title, book.IDs, book.Extra)
+			}
+
+			if ids, want := chapterSummaries(got.ChaptersAndSubchapters()), chapterSummaries(book.ChaptersAndSubchapters()); !slices.Equal(ids, want) {
+				t.Errorf("chapters = %q, want %q", ids, want)
+			}
+
+			one := got.Chapters[0]
+			if html, err := one.Content.Format("html"); err != nil || html != "<p>First.</p>\n" {
+				t.Errorf("html of chapter one = %v, %v; want \"<p>First.</p>\\n\"", html, err)
+			}
+			if one.Book == nil || one.Book.UniqueID != got.UniqueID {
+				t.Errorf("Book of chapter one = %v, want the book", one.Book)
+			}
+			if one.Chapters[0].Parent == nil || one.Chapters[0].Parent.UniqueID != "one" || one.Next == nil || one.Next.UniqueID != "one-one" {
+				t.Errorf("links of chapter one are not restored")
+			}
+
+			if len(got.ReadingOrders) != 1 || len(got.ReadingOrders[0].Chapters) != 3 || got.ReadingOrders[0].Chapters[0].UniqueID != "appendix" {
+				t.Errorf("reading orders = %+v, want the reverse order of 3 chapters", got.ReadingOrders)
+			}
+
+			if terms := one.Terms("pov"); len(terms) != 1 || terms[0].Name != "Mira" {
+				t.Errorf("terms of chapter one = %+v, want Mira", terms)
+			}
+
+			if len(got.Translations) != 1 {
+				t.Fatalf("got %d translations, want 1", len(got.Translations))
+			}
+			es := got.Translations[0]
+			if es.LanguageCode != "es" || es.Title != "Libro" || es.Chapters[0].Title != "Uno" || len(es.ReadingOrders) != 1 {
+				t.Errorf("translation = %q, %q, %q with %d reading orders; want \"es\", \"Libro\", \"Uno\" with 1", es.LanguageCode, es.Title, es.Chapters[0].Title, len(es.ReadingOrders))
+			}
+			if len(got.Languages) != 2 || got.Languages[1].Dir != "es/" {
+				t.Errorf("languages = %+v, want en and es", got.Languages)
+			}
+		})
+	}
+}
+
+func TestModelFormatUnknown(t *testing.T) {
+	var unknown ErrModelFormatUnknown
+	if _, err := MarshalModel(&Book{}, "toml"); !errors.As(err, &unknown) {
+		t.Errorf("MarshalModel() error = %v, want an ErrModelFormatUnknown", err)
+	}
+	if _, err := UnmarshalModel(nil, "toml"); !errors.As(err, &unknown) {
+		t.Errorf("UnmarshalModel() error = %v, want an ErrModelFormatUnknown", err)
+	}
+}
+
+// chapterSummaries returns the unique ID, title, number and kind of each chapter.
+func chapterSummaries(chapters []*Chapter) []string {
+	var summaries []string
+	for _, chapter := range chapters {
+		summaries = append(summaries, chapter.UniqueID+"|"+chapter.Title+"|"+chapter.Number()+"|"+chapter.Kind.String())
+	}
+
+	return summaries
+}
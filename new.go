@@ -1,157 +1,782 @@
 package pub
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 )
 
 const (
 	BookConfigFileName         = "pub.yml"
 	BookChaptersConfigFileName = "nav.yml"
+	BookProfilesConfigFileName = "profiles.yml"
 	BookAssetsDirName          = "assets"
 	BookChaptersDirName        = "chapters"
+	BookTranslationsDirName    = "translations"
+
+	ChapterDirectoryIndexFileName = "index.md"
+)
+
+var (
+	ChapterContentFileExtensions = []string{".md", ".markdown"}
 )
 
-func NewBook(inputPath string) (Book, error) {
+// BookOption configures how a [Book] is loaded by [NewBookFS].
+type BookOption func(*bookOptions)
+
+type bookOptions struct {
+	baseDir     string
+	strict      bool
+	profile     string
+	lazyContent bool
+	drafts      bool
+	buildTime   time.Time
+	inLibrary   bool
+}
+
+// WithBaseDirectory sets the directory that InputPath fields are relative to.
+func WithBaseDirectory(dir string) BookOption {
+	return func(o *bookOptions) {
+		o.baseDir = dir
+	}
+}
+
+// WithStrict reports unknown YAML keys as errors instead of warnings.
+func WithStrict() BookOption {
+	return func(o *bookOptions) {
+		o.strict = true
+	}
+}
+
+// WithProfile merges the build profile named name over the book's config.
+func WithProfile(name string) BookOption {
+	return func(o *bookOptions) {
+		o.profile = name
+	}
+}
+
+// WithLazyContent reads the content of chapters on first access instead of while
+// loading the book. See [Content.Release].
+func WithLazyContent() BookOption {
+	return func(o *bookOptions) {
+		o.lazyContent = true
+	}
+}
+
+// WithDrafts includes draft and scheduled chapters, which are otherwise left out.
+func WithDrafts() BookOption {
+	return func(o *bookOptions) {
+		o.drafts = true
+	}
+}
+
+// WithBuildTime sets the time chapters are scheduled after, instead of now.
+func WithBuildTime(t time.Time) BookOption {
+	return func(o *bookOptions) {
+		o.buildTime = t
+	}
+}
+
+// NewBook loads the book project at the directory inputPath. See [NewBookFS].
+func NewBook(inputPath string, opts ...BookOption) (Book, error) {
+	absPath, err := filepath.Abs(inputPath)
+	if err != nil {
+		return Book{}, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+	}
+
+	opts = append([]BookOption{WithBaseDirectory(absPath)}, opts...)
+
+	return NewBookFS(os.DirFS(absPath), ".", opts...)
+}
+
+// NewBookFS loads the book project at the directory root of fsys.
+func NewBookFS(fsys fs.FS, root string, opts ...BookOption) (Book, error) {
 	var book Book
-	if err := book.SetInputPath(inputPath); err != nil {
-		return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+	book.fsys = fsys
+	for _, opt := range opts {
+		opt(&book.options)
+	}
+	book.root = root
+	book.InputPath = book.inputPathOf(root)
+
+	if err := book.load(nil); err != nil {
+		return book, fmt.Errorf("[BOOK] \"%s\": %w", book.InputPath, err)
+	}
+
+	if err := book.loadTranslations(); err != nil {
+		return book, err
+	}
+
+	return book, nil
+}
+
+// load loads the book at its root directory, as a translation of original if
+// it is not nil.
+func (b *Book) load(original *Book) error {
+	var overrides []string
+	configPath := path.Join(b.root, BookConfigFileName)
+	if original != nil {
+		if _, err := fs.Stat(b.fsys, configPath); err == nil {
+			overrides = append(overrides, configPath)
+		}
+		configPath = path.Join(original.root, BookConfigFileName)
+	}
+
+	if err := b.loadConfig(configPath, overrides...); err != nil {
+		return err
+	}
+	b.Schema = CurrentSchemaVersion
+
+	if original != nil {
+		// a translation is in the language of its directory, and shares the original's UUID
+		if b.LanguageCode == original.LanguageCode {
+			b.LanguageCode = path.Base(b.root)
+		}
+		if b.UUID() == "" && original.UUID() != "" {
+			b.IDs = maps.Clone(b.IDs)
+			if b.IDs == nil {
+				b.IDs = make(Identifiers)
+			}
+			b.IDs[b.IDs.key(IdentifierUUID)] = original.UUID()
+		}
 	}
 
-	if err := unmarshalFromYAMLFile(filepath.Join(book.InputPath, BookConfigFileName), &book); err != nil {
-		return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+	if err := loadLicenseTexts(b.Copyright.Licenses, b); err != nil {
+		return err
 	}
 
-	assets, err := newAssets(filepath.Join(book.InputPath, BookAssetsDirName))
+	if err := newProfiles(b.sourcePath(BookProfilesConfigFileName), b); err != nil {
+		return err
+	}
+
+	assets, err := newAssets(b.sourcePath(BookAssetsDirName), b)
 	if err != nil {
-		return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+		return err
 	}
-	book.Assets = assets
+	b.Assets = assets
 
-	if len(book.Content.Raw) == 0 {
-		raw, err := os.ReadFile(filepath.Join(book.InputPath, "index.md"))
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+	if len(b.Content.Raw) == 0 {
+		raw, err := fs.ReadFile(b.fsys, b.sourcePath("index.md"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
 		}
-		book.Content.Raw = raw
+		b.Content.Raw = raw
 	}
 
-	chapters, err := newChapters(book.InputPath, &book)
+	chapters, err := newChapters(b)
 	if err != nil {
-		return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+		return err
 	}
-	book.Chapters = chapters
+	b.Chapters = chapters
 
-	if err := book.EnsureValid(); err != nil {
-		return book, fmt.Errorf("[BOOK] \"%s\": %w", inputPath, err)
+	if original != nil {
+		b.alignChapterIDs(original)
 	}
 
-	return book, nil
+	b.normalize()
+
+	b.ensureUUID()
+
+	if err := b.EnsureValid(); err != nil {
+		return err
+	}
+
+	// dates are compared once the book's time zone is applied to them
+	b.Chapters = b.publishedChapters(b.Chapters)
+	b.aggregateAdvisories()
+	orderChapters(b.Chapters)
+	// the hierarchy is only set once withholding and reordering have moved chapters
+	setChapterHierarchy(b.Chapters, nil)
+	linkChapters(b.Chapters)
+	b.linkReadingOrders()
+	b.linkTaxonomies()
+
+	return nil
 }
 
-func newAssets(inputPath string) ([]Asset, error) {
-	var assets []Asset
+// sourcePath returns the path of name, which translations may take from the original.
+func (b Book) sourcePath(name string) string {
+	p := path.Join(b.root, name)
+	if b.originalRoot == "" {
+		return p
+	}
+
+	if _, err := fs.Stat(b.fsys, p); err == nil {
+		return p
+	}
+
+	return path.Join(b.originalRoot, name)
+}
 
-	items, err := os.ReadDir(inputPath)
+// newProfiles adds the profiles of the registry file at inputPath, if any, to
+// those of pub.yml.
+func newProfiles(inputPath string, book *Book) error {
+	book.profilesPath = book.inputPathOf(inputPath)
+
+	var profiles map[string]Profile
+	err := book.unmarshalFromYAMLFile(inputPath, &profiles)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if book.ProfilesRegistry == nil {
+		book.ProfilesRegistry = make(map[string]Profile, len(profiles))
+	}
+
+	for id, profile := range profiles {
+		if _, ok := book.ProfilesRegistry[id]; !ok {
+			book.ProfilesRegistry[id] = profile
+		}
+	}
+
+	return nil
+}
+
+// newAssets discovers the assets in the assets directory at inputPath.
+func newAssets(inputPath string, book *Book) ([]Asset, error) {
+	var names []string
+	err := fs.WalkDir(book.fsys, inputPath, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if name != inputPath && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasPrefix(d.Name(), ".") {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	for _, item := range items {
-		if item.IsDir() {
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		files[name] = true
+	}
+
+	var assets []Asset
+	groups := make(map[string]int)
+	for _, name := range names {
+		rel := strings.TrimPrefix(strings.TrimPrefix(name, inputPath), "/")
+		if isAssetSidecar(rel, files, inputPath) {
 			continue
 		}
 
-		// TODO: properly create asset (detecting mime type format, etc.)
-		asset := Asset{
-			Objects: []AssetDescriptor{
-				{
-					Name: item.Name(),
-				},
-			},
+		format, err := detectAssetFormat(book.fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		mediaType, _, _ := strings.Cut(format, "/")
+
+		group := strings.TrimSuffix(rel, path.Ext(rel))
+		i, ok := groups[group]
+		if !ok {
+			i = len(assets)
+			groups[group] = i
+			assets = append(assets, Asset{Name: group})
+		}
+
+		assets[i].Objects = append(assets[i].Objects, AssetDescriptor{
+			Name:     rel,
+			Type:     mediaType,
+			Format:   format,
+			declared: declaredAssetFormat(rel),
+		})
+	}
+
+	for i := range assets {
+		asset := &assets[i]
+		asset.loc = at(book.inputPathOf(path.Join(inputPath, asset.Objects[0].Name)), "$")
+
+		var sidecar assetSidecar
+		for _, object := range asset.Objects {
+			sidecarPath := path.Join(inputPath, object.Name+AssetSidecarFileExtension)
+			if _, err := fs.Stat(book.fsys, sidecarPath); err != nil {
+				continue
+			}
+
+			if err := book.unmarshalFromYAMLFile(sidecarPath, &sidecar); err != nil {
+				return nil, err
+			}
+			asset.loc = at(book.inputPathOf(sidecarPath), "$")
+			break
 		}
-		assets = append(assets, asset)
+
+		asset.AlternativeText = sidecar.AlternativeText
+		asset.Caption = sidecar.Caption
+		asset.order = sidecar.Order
+		sortAssetDescriptors(asset.Objects, sidecar.Order)
 	}
 
 	return assets, nil
 }
 
-func newChapters(booksDir string, book *Book) ([]Chapter, error) {
+// isAssetSidecar reports whether the file rel is the sidecar of another in files.
+func isAssetSidecar(rel string, files map[string]bool, inputPath string) bool {
+	if !strings.HasSuffix(rel, AssetSidecarFileExtension) {
+		return false
+	}
+
+	return files[path.Join(inputPath, strings.TrimSuffix(rel, AssetSidecarFileExtension))]
+}
+
+func newChapters(book *Book) ([]Chapter, error) {
+	navPath := book.sourcePath(BookChaptersConfigFileName)
+
+	// globs, discovery and reading_orders.yml are relative to the nav.yml in use
+	chaptersDir := path.Join(path.Dir(navPath), BookChaptersDirName)
+
 	var chapters []Chapter
-	if err := unmarshalFromYAMLFile(filepath.Join(booksDir, BookChaptersConfigFileName), &chapters); err != nil {
+	var err error
+	if isYAMLMappingFile(book.fsys, navPath) {
+		var nav navConfig
+		err = book.unmarshalFromYAMLFile(navPath, &nav)
+		chapters = nav.Chapters
+		setChapterLocations(chapters, at(book.inputPathOf(navPath), "$"), ".chapters")
+		book.addReadingOrders(nav.ReadingOrders, at(book.inputPathOf(navPath), "$.reading_orders"))
+	} else {
+		err = book.unmarshalFromYAMLFile(navPath, &chapters)
+		setChapterLocations(chapters, at(book.inputPathOf(navPath), "$"), "")
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return chapters, err
 	}
 
-	var allChapters []*Chapter
+	var orders []ReadingOrder
+	ordersPath := path.Join(path.Dir(navPath), BookReadingOrdersConfigFileName)
+	err = book.unmarshalFromYAMLFile(ordersPath, &orders)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return chapters, err
+	}
+	book.addReadingOrders(orders, at(book.inputPathOf(ordersPath), "$"))
+
+	// zero-config: nav.yml is missing or empty, so discover chapters from the chapters directory
+	if len(chapters) == 0 {
+		chapters, err = discoverChapters(book.fsys, chaptersDir, "")
+		if err != nil {
+			return chapters, err
+		}
+	} else {
+		chapters, err = expandChapterGlobs(book.fsys, chapters, chaptersDir, referencedContentFileNames(chapters))
+		if err != nil {
+			return chapters, err
+		}
+	}
+
 	for i := range chapters {
 		chapter := &chapters[i]
-		if err := decodeChapter(chapter, book, filepath.Join(booksDir, BookChaptersDirName)); err != nil {
+		if err := decodeChapter(chapter, book); err != nil {
 			return chapters, err
 		}
-		allChapters = append(allChapters, chapter)
-		allChapters = append(allChapters, chapter.Subchapters()...)
 	}
 
-	for i := range allChapters {
-		chapter := allChapters[i]
+	return chapters, nil
+}
+
+// navConfig is the mapping form of nav.yml, which defines reading orders along with the chapters.
+type navConfig struct {
+	Chapters      []Chapter      `json:"chapters"`
+	ReadingOrders []ReadingOrder `json:"reading_orders"`
+}
+
+// isYAMLMappingFile reports whether the root of the YAML file at name is a mapping.
+func isYAMLMappingFile(fsys fs.FS, name string) bool {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return false
+	}
+
+	f, err := parser.ParseBytes(data, 0)
+	if err != nil || len(f.Docs) == 0 {
+		return false
+	}
+
+	switch f.Docs[0].Body.(type) {
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return true
+	}
+
+	return false
+}
+
+// addReadingOrders adds orders (decoded from the YAML list at loc) to the book's reading orders.
+func (b *Book) addReadingOrders(orders []ReadingOrder, loc location) {
+	for i := range orders {
+		orders[i].loc = loc.join(fmt.Sprintf("[%d]", i))
+	}
+
+	b.ReadingOrders = append(b.ReadingOrders, orders...)
+}
+
+// linkChapters sets the previous and next chapter of each readable chapter, in
+// reading order.
+func linkChapters(chapters []Chapter) {
+	var linked []*Chapter
+	for _, chapter := range allChapters(&chapters) {
+		chapter.Previous, chapter.Next = nil, nil
+		if !chapter.IsPlaceholder() && !chapter.IsDivision() && !chapter.InSplitVolume() {
+			linked = append(linked, chapter)
+		}
+	}
 
+	for i, chapter := range linked {
 		if i-1 >= 0 {
-			chapter.Previous = allChapters[i-1]
+			chapter.Previous = linked[i-1]
+		}
+
+		if i+1 < len(linked) {
+			chapter.Next = linked[i+1]
+		}
+	}
+}
+
+// discoverChapters creates a chapter for each Markdown file and subdirectory of
+// dir in natural order. A subdirectory's content is read from its index.md.
+func discoverChapters(fsys fs.FS, chaptersDir, dir string) ([]Chapter, error) {
+	items, err := fs.ReadDir(fsys, path.Join(chaptersDir, dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(items, func(a, b fs.DirEntry) int {
+		return naturalCompareFileNames(a.Name(), b.Name())
+	})
+
+	var chapters []Chapter
+	for _, item := range items {
+		name := item.Name()
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		rel := path.Join(dir, name)
+		if !item.IsDir() {
+			if dir != "" && name == ChapterDirectoryIndexFileName {
+				continue
+			}
+
+			if isChapterContentFile(name) && !isAuthorsNoteFile(name) {
+				chapters = append(chapters, Chapter{ContentFileName: rel})
+			}
+			continue
 		}
 
-		if i+1 < len(allChapters) {
-			chapter.Next = allChapters[i+1]
+		subchapters, err := discoverChapters(fsys, chaptersDir, rel)
+		if err != nil {
+			return nil, err
+		}
+
+		chapter := Chapter{
+			UniqueID: name,
+			Chapters: subchapters,
+		}
+
+		index := path.Join(rel, ChapterDirectoryIndexFileName)
+		if _, err := fs.Stat(fsys, path.Join(chaptersDir, index)); err == nil {
+			chapter.ContentFileName = index
+		} else if len(subchapters) == 0 {
+			continue
 		}
+
+		chapters = append(chapters, chapter)
 	}
 
 	return chapters, nil
 }
 
-func decodeChapter(chapter *Chapter, book *Book, chaptersDir string) error {
-	if err := chapter.SetBook(book); err != nil {
-		return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+// expandChapterGlobs replaces each chapter with a Glob by a chapter for each
+// matching file not already in seen, in natural order.
+func expandChapterGlobs(fsys fs.FS, chapters []Chapter, chaptersDir string, seen map[string]bool) ([]Chapter, error) {
+	var expanded []Chapter
+	for _, chapter := range chapters {
+		if chapter.Glob == "" {
+			subchapters, err := expandChapterGlobs(fsys, chapter.Chapters, chaptersDir, seen)
+			if err != nil {
+				return nil, err
+			}
+			chapter.Chapters = subchapters
+
+			expanded = append(expanded, chapter)
+			continue
+		}
+
+		if chapter.ContentFileName != "" || len(chapter.Chapters) > 0 {
+			return nil, ErrChapterGlobWithContent{Glob: chapter.Glob}
+		}
+
+		matches, err := fs.Glob(fsys, path.Join(chaptersDir, chapter.Glob))
+		if err != nil {
+			return nil, fmt.Errorf("glob \"%s\": %w", chapter.Glob, err)
+		}
+		slices.SortFunc(matches, naturalCompareFileNames)
+
+		for _, match := range matches {
+			info, err := fs.Stat(fsys, match)
+			if err != nil {
+				return nil, err
+			}
+			if info.IsDir() || isAuthorsNoteFile(match) {
+				continue
+			}
+
+			rel := strings.TrimPrefix(strings.TrimPrefix(match, chaptersDir), "/")
+			if seen[rel] {
+				continue
+			}
+			seen[rel] = true
+
+			expanded = append(expanded, Chapter{ContentFileName: rel, loc: chapter.loc})
+		}
+	}
+
+	return expanded, nil
+}
+
+// setChapterHierarchy sets the parent, depth, sibling index and number path of
+// each chapter, where parent contains chapters.
+func setChapterHierarchy(chapters []Chapter, parent *Chapter) {
+	var number, appendixNumber int
+	for i := range chapters {
+		chapter := &chapters[i]
+		chapter.Parent = parent
+		chapter.Index = i
+		chapter.NumberPath = nil
+
+		chapter.Depth = 0
+		if parent != nil {
+			chapter.Depth = parent.Depth + 1
+		}
+
+		if chapter.Kind.isNumbered() && (parent == nil || parent.NumberPath != nil) {
+			n := &number
+			if chapter.Kind == ChapterKindAppendix {
+				n = &appendixNumber
+			}
+			*n++
+
+			var parentPath []int
+			if parent != nil {
+				parentPath = parent.NumberPath
+			}
+			chapter.NumberPath = append(slices.Clone(parentPath), *n)
+		}
+
+		setChapterHierarchy(chapter.Chapters, chapter)
+	}
+}
+
+// setChapterLocations sets the location of each chapter in nav.yml, relative to loc.
+func setChapterLocations(chapters []Chapter, loc location, field string) {
+	for i := range chapters {
+		chapter := &chapters[i]
+		chapter.loc = loc.join(fmt.Sprintf("%s[%d]", field, i))
+		setChapterLocations(chapter.Chapters, chapter.loc, ".chapters")
 	}
+}
 
-	if chapter.InputPath == "" && chapter.ContentFileName != "" {
-		if err := chapter.SetInputPath(filepath.Join(chaptersDir, chapter.ContentFileName)); err != nil {
-			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.ContentFileName, err)
+func referencedContentFileNames(chapters []Chapter) map[string]bool {
+	names := make(map[string]bool)
+	for _, chapter := range allChapters(&chapters) {
+		if chapter.ContentFileName != "" {
+			names[path.Clean(filepath.ToSlash(chapter.ContentFileName))] = true
 		}
 	}
 
-	if chapter.InputPath != "" {
-		raw, err := os.ReadFile(chapter.InputPath)
+	return names
+}
+
+func isChapterContentFile(name string) bool {
+	return slices.Contains(ChapterContentFileExtensions, strings.ToLower(path.Ext(name)))
+}
+
+func decodeChapter(chapter *Chapter, book *Book) error {
+	if err := chapter.SetBook(book); err != nil {
+		return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+	}
+
+	if chapter.ContentFileName != "" {
+		name := path.Join(BookChaptersDirName, filepath.ToSlash(chapter.ContentFileName))
+		contentPath := book.sourcePath(name)
+		chapter.InputPath = book.inputPathOf(contentPath)
+
+		var raw []byte
+		var err error
+		if book.options.lazyContent {
+			raw, err = readFrontMatter(book.fsys, contentPath)
+		} else {
+			raw, err = fs.ReadFile(book.fsys, contentPath)
+		}
 		if err != nil {
 			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
 		}
-		chapter.Content.Raw = raw
+
+		if err := decodeChapterFrontMatter(chapter, raw); err != nil {
+			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+		}
+
+		if err := loadAuthorsNoteFiles(book.fsys, chapter, contentPath); err != nil {
+			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+		}
+
+		if book.options.lazyContent {
+			// scan the rest of the file for authors' notes, without keeping the content in memory
+			offset := len(raw)
+			before, after, bodySize, err := scanAuthorsNotes(book.fsys, contentPath, offset, io.Discard)
+			if err != nil {
+				return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+			}
+			setAuthorsNoteSections(chapter, before, after)
+
+			// the front matter is skipped by its length rather than parsed again on every read
+			chapter.Content = lazyContent(func() ([]byte, error) {
+				var buf bytes.Buffer
+				buf.Grow(bodySize)
+				if _, _, _, err := scanAuthorsNotes(book.fsys, contentPath, offset, &buf); err != nil {
+					return nil, err
+				}
+				return buf.Bytes(), nil
+			}, bodySize)
+		} else {
+			var buf bytes.Buffer
+			before, after, _, err := splitAuthorsNotes(bytes.NewReader(chapter.Content.Raw), &buf)
+			if err != nil {
+				return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+			}
+			setAuthorsNoteSections(chapter, before, after)
+			chapter.Content.Raw = buf.Bytes()
+		}
+
+		if len(chapter.loc) == 0 {
+			chapter.loc = at(chapter.InputPath, "$")
+		}
+
+		// the chapter of a translation is untranslated when its content is that of the original
+		if book.originalRoot != "" && contentPath != path.Join(book.root, name) {
+			chapter.Untranslated = true
+		}
+	}
+
+	if err := loadLicenseTexts(chapter.Copyright.Licenses, book); err != nil {
+		return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
 	}
 
 	for i := range chapter.Subchapters() {
 		subchapter := chapter.Subchapters()[i]
-		if err := decodeChapter(subchapter, book, chaptersDir); err != nil {
+		if err := decodeChapter(subchapter, book); err != nil {
 			return err
 		}
 	}
 
-	if err := chapter.EnsureValid(); err != nil {
-		return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+	return nil
+}
+
+// loadLicenseTexts reads the Text of each license from its FileName, relative to
+// the assets directory or else the book's directory.
+func loadLicenseTexts(licenses []License, book *Book) error {
+	for i := range licenses {
+		license := &licenses[i]
+		if license.FileName == "" || license.Text != "" {
+			continue
+		}
+
+		name := filepath.ToSlash(license.FileName)
+		text, err := fs.ReadFile(book.fsys, book.sourcePath(path.Join(BookAssetsDirName, name)))
+		if errors.Is(err, fs.ErrNotExist) {
+			text, err = fs.ReadFile(book.fsys, book.sourcePath(name))
+		}
+		if err != nil {
+			return fmt.Errorf("license: %w", err)
+		}
+		license.Text = string(text)
 	}
 
 	return nil
 }
 
-func unmarshalFromYAMLFile(inputPath string, m any) error {
-	data, err := os.ReadFile(inputPath)
+// decodeChapterFrontMatter sets the chapter's content to raw without its front
+// matter, which it decodes into the chapter.
+func decodeChapterFrontMatter(chapter *Chapter, raw []byte) error {
+	frontMatter, body, ok := splitFrontMatter(raw)
+	chapter.Content.Raw = body
+	if !ok {
+		return nil
+	}
+
+	var fm Chapter
+	if err := yaml.Unmarshal(frontMatter, &fm); err != nil {
+		return fmt.Errorf("parsing front matter: %w", err)
+	}
+
+	if len(fm.Chapters) > 0 {
+		return ErrChapterFrontMatterHasChapters
+	}
+
+	if fm.ContentFileName != "" {
+		return ErrChapterFrontMatterHasContentFileName
+	}
+
+	navExtra := chapter.Extra
+	if err := yaml.Unmarshal(frontMatter, chapter); err != nil {
+		return fmt.Errorf("parsing front matter: %w", err)
+	}
+	chapter.Extra = mergeExtra(navExtra, fm.Extra)
+	chapter.Content.Raw = body
+
+	// front matter starts on the line after the opening delimiter
+	if chapter.Book != nil {
+		chapter.Book.addSource(chapter.InputPath, frontMatter, 1, reflect.TypeFor[Chapter]())
+	}
+	chapter.loc = append(at(chapter.InputPath, "$"), chapter.loc...)
+
+	return nil
+}
+
+func (b *Book) unmarshalFromYAMLFile(inputPath string, m any) error {
+	data, err := fs.ReadFile(b.fsys, inputPath)
 	if err != nil {
-		return fmt.Errorf("parsing \"%s\": %w", filepath.Base(inputPath), err)
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(inputPath), err)
+	}
+	if b.sourceSchema < CurrentSchemaVersion {
+		data, err = migrateYAML(data, b.sourceSchema, m)
+		if err != nil {
+			return fmt.Errorf("parsing \"%s\": %w", path.Base(inputPath), err)
+		}
 	}
+	b.addSource(b.inputPathOf(inputPath), data, 0, reflect.TypeOf(m).Elem())
 
 	if err := yaml.Unmarshal(data, m); err != nil {
-		return fmt.Errorf("parsing \"%s\": %w", filepath.Base(inputPath), err)
+		return fmt.Errorf("parsing \"%s\": %w", path.Base(inputPath), err)
 	}
 
 	return nil
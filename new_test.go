@@ -0,0 +1,367 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDecodeChapterFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		nav     Chapter
+		raw     string
+		title   string
+		extra   map[string]any
+		body    string
+		wantErr error
+	}{
+		{
+			name:  "no front matter",
+			nav:   Chapter{Title: "Nav"},
+			raw:   "# Body\n",
+			title: "Nav",
+			body:  "# Body\n",
+		},
+		{
+			name:  "front matter overrides nav.yml",
+			nav:   Chapter{Title: "Nav"},
+			raw:   "---\ntitle: Front\n---\n# Body\n",
+			title: "Front",
+			body:  "# Body\n",
+		},
+		{
+			name:  "nav.yml fields are kept",
+			nav:   Chapter{Title: "Nav"},
+			raw:   "---\nsubtitle: Sub\n---\nbody",
+			title: "Nav",
+			body:  "body",
+		},
+		{
+			name:  "extra keys are merged",
+			nav:   Chapter{Extra: map[string]any{"a": "nav", "b": "nav"}},
+			raw:   "---\nextra:\n  b: front\n  c: front\n---\n",
+			extra: map[string]any{"a": "nav", "b": "front", "c": "front"},
+		},
+		{
+			name:    "chapters",
+			raw:     "---\nchapters:\n  - title: A\n---\n",
+			wantErr: ErrChapterFrontMatterHasChapters,
+		},
+		{
+			name:    "content file name",
+			raw:     "---\ncontent_file_name: other.md\n---\n",
+			wantErr: ErrChapterFrontMatterHasContentFileName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chapter := tt.nav
+			err := decodeChapterFrontMatter(&chapter, []byte(tt.raw))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decodeChapterFrontMatter() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if chapter.Title != tt.title {
+				t.Errorf("Title = %q, want %q", chapter.Title, tt.title)
+			}
+			if string(chapter.Content.Raw) != tt.body {
+				t.Errorf("Content.Raw = %q, want %q", chapter.Content.Raw, tt.body)
+			}
+			if tt.extra != nil && !reflect.DeepEqual(chapter.Extra, tt.extra) {
+				t.Errorf("Extra = %v, want %v", chapter.Extra, tt.extra)
+			}
+		})
+	}
+}
+
+func TestExpandChapterGlobs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chapters/chapter-1.md":             {},
+		"chapters/chapter-2.md":             {},
+		"chapters/chapter-10.md":            {},
+		"chapters/chapter-1.note-before.md": {},
+		"chapters/part-2/a.md":              {},
+		"chapters/part-2/b.md":              {},
+		"chapters/part-2/nested/c.md":       {},
+	}
+
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		seen     []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "natural order without authors' notes",
+			chapters: []Chapter{{Glob: "*.md"}},
+			want:     []string{"chapter-1.md", "chapter-2.md", "chapter-10.md"},
+		},
+		{
+			name:     "skips directories",
+			chapters: []Chapter{{Glob: "part-2/*"}},
+			want:     []string{"part-2/a.md", "part-2/b.md"},
+		},
+		{
+			name:     "skips seen files",
+			chapters: []Chapter{{Glob: "*.md"}},
+			seen:     []string{"chapter-2.md"},
+			want:     []string{"chapter-1.md", "chapter-10.md"},
+		},
+		{
+			name:     "later globs skip earlier matches",
+			chapters: []Chapter{{ContentFileName: "chapter-10.md"}, {Glob: "chapter-1*.md"}, {Glob: "*.md"}},
+			seen:     []string{"chapter-10.md"},
+			want:     []string{"chapter-10.md", "chapter-1.md", "chapter-2.md"},
+		},
+		{
+			name:     "subchapters",
+			chapters: []Chapter{{ContentFileName: "chapter-1.md", Chapters: []Chapter{{Glob: "part-2/*.md"}}}},
+			want:     []string{"chapter-1.md", "part-2/a.md", "part-2/b.md"},
+		},
+		{
+			name:     "no matches",
+			chapters: []Chapter{{Glob: "missing/*.md"}},
+		},
+		{
+			name:     "glob with content",
+			chapters: []Chapter{{Glob: "*.md", ContentFileName: "chapter-1.md"}},
+			wantErr:  true,
+		},
+		{
+			name:     "bad pattern",
+			chapters: []Chapter{{Glob: "[.md"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			for _, name := range tt.seen {
+				seen[name] = true
+			}
+
+			chapters, err := expandChapterGlobs(fsys, tt.chapters, "chapters", seen)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandChapterGlobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, chapter := range flattenChapters(chapters) {
+				got = append(got, chapter.ContentFileName)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandChapterGlobs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBookFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":              {Data: []byte("unique_id: test-book\ntitle: Test Book\nlanguage_code: en\n")},
+		"book/nav.yml":              {Data: []byte("- content_file_name: one.md\n  chapters:\n    - glob: one-*.md\n- title: Two\n  content_file_name: two.md\n")},
+		"book/index.md":             {Data: []byte("Welcome.\n")},
+		"book/chapters/one.md":      {Data: []byte("---\ntitle: One\n---\nFirst.\n")},
+		"book/chapters/one-2.md":    {Data: []byte("---\ntitle: One.Two\n---\n")},
+		"book/chapters/one-10.md":   {Data: []byte("---\ntitle: One.Ten\n---\n")},
+		"book/chapters/two.md":      {Data: []byte("Second.\n<!-- note-after -->\nThanks.\n<!-- /note-after -->\n")},
+		"book/chapters/unlisted.md": {Data: []byte("Not in nav.yml.\n")},
+	}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	if book.UniqueID != "test-book" || book.Title != "Test Book" || book.LanguageCode != "en" {
+		t.Errorf("book = %q, %q, %q; want \"test-book\", \"Test Book\", \"en\"", book.UniqueID, book.Title, book.LanguageCode)
+	}
+	if string(book.Content.Raw) != "Welcome.\n" {
+		t.Errorf("Content.Raw = %q, want \"Welcome.\\n\"", book.Content.Raw)
+	}
+	if book.UUID() == "" || !book.HasGeneratedUUID() {
+		t.Errorf("UUID() = %q, HasGeneratedUUID() = %v; want a generated UUID", book.UUID(), book.HasGeneratedUUID())
+	}
+
+	var titles, numbers []string
+	for _, chapter := range book.ChaptersAndSubchapters() {
+		titles = append(titles, chapter.Title)
+		numbers = append(numbers, chapter.Number())
+	}
+	if want := []string{"One", "One.Two", "One.Ten", "Two"}; !slices.Equal(titles, want) {
+		t.Errorf("chapter titles = %q, want %q", titles, want)
+	}
+	if want := []string{"1", "1.1", "1.2", "2"}; !slices.Equal(numbers, want) {
+		t.Errorf("chapter numbers = %q, want %q", numbers, want)
+	}
+
+	two := book.Chapters[1]
+	content, err := two.Content.Bytes()
+	if err != nil {
+		t.Fatalf("Content.Bytes() error = %v", err)
+	}
+	if string(content) != "Second.\n" || string(two.AuthorsNoteSuffix.Raw) != "Thanks." {
+		t.Errorf("chapter two = %q with note %q, want \"Second.\\n\" with note \"Thanks.\"", content, two.AuthorsNoteSuffix.Raw)
+	}
+	if two.Previous == nil || two.Previous.Title != "One.Ten" {
+		t.Errorf("Previous of chapter two = %v, want One.Ten", two.Previous)
+	}
+	if two.Book == nil || two.Book.UniqueID != book.UniqueID {
+		t.Errorf("Book of chapter two = %v, want the book", two.Book)
+	}
+}
+
+func TestNewBookFSErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing pub.yml", fstest.MapFS{"book/index.md": {}}},
+		{"missing unique ID", fstest.MapFS{"book/pub.yml": {Data: []byte("title: A\nlanguage_code: en\n")}}},
+		{"missing content file", fstest.MapFS{
+			"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+			"book/nav.yml": {Data: []byte("- content_file_name: missing.md\n")},
+		}},
+		{"glob with content", fstest.MapFS{
+			"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+			"book/nav.yml": {Data: []byte("- glob: '*.md'\n  content_file_name: one.md\n")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewBookFS(tt.fsys, "book"); err == nil {
+				t.Errorf("NewBookFS() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestSetChapterHierarchy(t *testing.T) {
+	type row struct {
+		title  string
+		depth  int
+		path   []int
+		number string
+		local  string
+	}
+
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		want     []row
+	}{
+		{
+			name: "nested chapters",
+			chapters: []Chapter{
+				{Title: "One", Chapters: []Chapter{
+					{Title: "One.One"},
+					{Title: "One.Two", Chapters: []Chapter{
+						{Title: "One.Two.One"},
+					}},
+				}},
+				{Title: "Two"},
+			},
+			want: []row{
+				{"One", 0, []int{1}, "1", "1"},
+				{"One.One", 1, []int{1, 1}, "1.1", "1"},
+				{"One.Two", 1, []int{1, 2}, "1.2", "2"},
+				{"One.Two.One", 2, []int{1, 2, 1}, "1.2.1", "1"},
+				{"Two", 0, []int{2}, "2", "2"},
+			},
+		},
+		{
+			name: "kinds",
+			chapters: []Chapter{
+				{Title: "Dedication", Kind: ChapterKindFrontMatter},
+				{Title: "Part", Kind: ChapterKindPart, Chapters: []Chapter{
+					{Title: "Chapter"},
+					{Title: "Interlude", Kind: ChapterKindInterlude},
+					{Title: "Chapter"},
+				}},
+				{Title: "Chapter", Chapters: []Chapter{
+					{Title: "Section"},
+				}},
+				{Title: "Interlude", Kind: ChapterKindInterlude, Chapters: []Chapter{
+					{Title: "Section"},
+				}},
+				{Title: "Appendix", Kind: ChapterKindAppendix, Chapters: []Chapter{
+					{Title: "Section"},
+				}},
+				{Title: "Appendix", Kind: ChapterKindAppendix},
+				{Title: "Afterword", Kind: ChapterKindBackMatter},
+			},
+			want: []row{
+				{"Dedication", 0, nil, "", ""},
+				{"Part", 0, []int{1}, "1", "1"},
+				{"Chapter", 1, []int{1, 1}, "1.1", "1"},
+				{"Interlude", 1, nil, "", ""},
+				{"Chapter", 1, []int{1, 2}, "1.2", "2"},
+				{"Chapter", 0, []int{2}, "2", "2"},
+				{"Section", 1, []int{2, 1}, "2.1", "1"},
+				{"Interlude", 0, nil, "", ""},
+				{"Section", 1, nil, "", ""},
+				{"Appendix", 0, []int{1}, "A", "A"},
+				{"Section", 1, []int{1, 1}, "A.1", "1"},
+				{"Appendix", 0, []int{2}, "B", "B"},
+				{"Afterword", 0, nil, "", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setChapterHierarchy(tt.chapters, nil)
+
+			got := flattenChapters(tt.chapters)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d chapters, want %d", len(got), len(tt.want))
+			}
+
+			for i, chapter := range got {
+				w := tt.want[i]
+				if chapter.Title != w.title || chapter.Depth != w.depth || !slices.Equal(chapter.NumberPath, w.path) || chapter.Number() != w.number || chapter.LocalNumber() != w.local {
+					t.Errorf("chapter %d = %s (depth %d, path %v, number %q, local %q), want %s (depth %d, path %v, number %q, local %q)", i, chapter.Title, chapter.Depth, chapter.NumberPath, chapter.Number(), chapter.LocalNumber(), w.title, w.depth, w.path, w.number, w.local)
+				}
+			}
+		})
+	}
+}
+
+func TestSetChapterHierarchyLinks(t *testing.T) {
+	chapters := []Chapter{
+		{Title: "One", Chapters: []Chapter{{Title: "One.One"}, {Title: "One.Two"}}},
+	}
+	setChapterHierarchy(chapters, nil)
+
+	sub := chapters[0].Chapters[1]
+	if sub.Parent != &chapters[0] {
+		t.Errorf("Parent of %q = %v, want %q", sub.Title, sub.Parent, chapters[0].Title)
+	}
+	if sub.Index != 1 {
+		t.Errorf("Index of %q = %d, want 1", sub.Title, sub.Index)
+	}
+	if chapters[0].Parent != nil || chapters[0].Depth != 0 {
+		t.Errorf("top-level chapter has parent %v and depth %d, want none and 0", chapters[0].Parent, chapters[0].Depth)
+	}
+}
+
+// flattenChapters returns the chapters and their subchapters, depth first.
+func flattenChapters(chapters []Chapter) []*Chapter {
+	var flattened []*Chapter
+	for i := range chapters {
+		flattened = append(flattened, &chapters[i])
+		flattened = append(flattened, flattenChapters(chapters[i].Chapters)...)
+	}
+
+	return flattened
+}
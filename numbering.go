@@ -0,0 +1,361 @@
+package pub
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type NumberingStyle int
+
+const (
+	Arabic NumberingStyle = iota
+	RomanUpper
+	RomanLower
+	LettersUpper
+	LettersLower
+	Words
+	WordsCapitalized
+)
+
+var (
+	NumberingStyleMap = map[string]NumberingStyle{
+		"arabic":            Arabic,
+		"roman":             RomanUpper,
+		"roman-lower":       RomanLower,
+		"letters":           LettersUpper,
+		"letters-lower":     LettersLower,
+		"words":             Words,
+		"words-capitalized": WordsCapitalized,
+	}
+)
+
+const (
+	DefaultNumberingSeparator = "."
+)
+
+// Numbering describes how the numbers of [Chapter]s are formatted. Styles[i]
+// applies at depth i, and the last style to any deeper levels.
+//
+// Appendices are numbered in the Appendices style, [LettersUpper] by default.
+type Numbering struct {
+	Separator  string           `json:"separator"`
+	Styles     []NumberingStyle `json:"styles"`
+	Appendices *NumberingStyle  `json:"appendices"`
+}
+
+// Style returns the numbering style used for chapters at the given depth.
+func (n Numbering) Style(depth int) NumberingStyle {
+	if len(n.Styles) == 0 {
+		return Arabic
+	}
+
+	return n.Styles[min(depth, len(n.Styles)-1)]
+}
+
+// AppendixStyle returns the numbering style used for appendices.
+func (n Numbering) AppendixStyle() NumberingStyle {
+	if n.Appendices == nil {
+		return LettersUpper
+	}
+
+	return *n.Appendices
+}
+
+// Format formats the number path (e.g. []int{1, 2, 1}) with the style of each
+// level, spelling out numbers in the language of languageCode.
+func (n Numbering) Format(path []int, languageCode string) string {
+	styles := make([]NumberingStyle, len(path))
+	for depth := range path {
+		styles[depth] = n.Style(depth)
+	}
+
+	return n.format(path, styles, languageCode)
+}
+
+// format formats each level of the number path with the style of the same index.
+func (n Numbering) format(path []int, styles []NumberingStyle, languageCode string) string {
+	separator := n.Separator
+	if separator == "" {
+		separator = DefaultNumberingSeparator
+	}
+
+	parts := make([]string, len(path))
+	for depth, number := range path {
+		parts[depth] = styles[depth].Format(number, languageCode)
+	}
+
+	return strings.Join(parts, separator)
+}
+
+type ErrNumberingStyleUnmarshalUnrecognized struct {
+	StyleString string
+}
+
+func (e ErrNumberingStyleUnmarshalUnrecognized) Error() string {
+	return fmt.Sprintf("numbering style: unrecognized value \"%s\" (value must be one of the following (case doesn't matter): %v)", e.StyleString, strings.Join(slices.Sorted(maps.Keys(NumberingStyleMap)), ", "))
+}
+
+type ErrNumberingStyleMarshalUnrecognized struct {
+	Value NumberingStyle
+}
+
+func (e ErrNumberingStyleMarshalUnrecognized) Error() string {
+	return fmt.Sprintf("numbering style: unrecognized value %d", int(e.Value))
+}
+
+func (s *NumberingStyle) UnmarshalText(text []byte) error {
+	vStr := string(text)
+
+	v, ok := NumberingStyleMap[strings.ToLower(vStr)]
+	if !ok {
+		return ErrNumberingStyleUnmarshalUnrecognized{StyleString: vStr}
+	}
+
+	*s = v
+
+	return nil
+}
+
+func (s NumberingStyle) MarshalText() ([]byte, error) {
+	for k, v := range NumberingStyleMap {
+		if v == s {
+			return []byte(k), nil
+		}
+	}
+
+	return nil, ErrNumberingStyleMarshalUnrecognized{Value: s}
+}
+
+// Format formats number in the style, or in Arabic numerals if it cannot.
+func (s NumberingStyle) Format(number int, languageCode string) string {
+	var formatted string
+
+	switch s {
+	case RomanUpper:
+		formatted = romanNumeral(number)
+	case RomanLower:
+		formatted = strings.ToLower(romanNumeral(number))
+	case LettersUpper:
+		formatted = letterNumeral(number)
+	case LettersLower:
+		formatted = strings.ToLower(letterNumeral(number))
+	case Words:
+		formatted = spelledOutNumber(number, languageCode)
+	case WordsCapitalized:
+		formatted = capitalize(spelledOutNumber(number, languageCode))
+	}
+
+	if formatted == "" {
+		return strconv.Itoa(number)
+	}
+
+	return formatted
+}
+
+func romanNumeral(number int) string {
+	if number <= 0 || number >= 4000 {
+		return ""
+	}
+
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var b strings.Builder
+	for i, value := range values {
+		for number >= value {
+			b.WriteString(symbols[i])
+			number -= value
+		}
+	}
+
+	return b.String()
+}
+
+// letterNumeral returns A-Z for 1-26, followed by AA-AZ, BA-BZ, etc.
+func letterNumeral(number int) string {
+	if number <= 0 {
+		return ""
+	}
+
+	var letters []byte
+	for number > 0 {
+		number--
+		letters = append([]byte{byte('A' + number%26)}, letters...)
+		number /= 26
+	}
+
+	return string(letters)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// spelledOutNumber returns number (1-999) in words in the language of
+// languageCode, or "" if it cannot.
+func spelledOutNumber(number int, languageCode string) string {
+	if number <= 0 || number >= 1000 {
+		return ""
+	}
+
+	switch baseLanguage(languageCode) {
+	case "", "en":
+		return englishNumber(number)
+	case "es":
+		return spanishNumber(number)
+	case "fr":
+		return frenchNumber(number)
+	case "de":
+		return germanNumber(number)
+	}
+
+	return ""
+}
+
+var (
+	englishOnes = []string{"", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+func englishNumber(number int) string {
+	var parts []string
+	if number >= 100 {
+		parts = append(parts, englishOnes[number/100]+" hundred")
+		number %= 100
+	}
+
+	switch {
+	case number == 0:
+	case number < 20:
+		parts = append(parts, englishOnes[number])
+	case number%10 == 0:
+		parts = append(parts, englishTens[number/10])
+	default:
+		parts = append(parts, englishTens[number/10]+"-"+englishOnes[number%10])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+var (
+	spanishOnes     = []string{"", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve", "diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete", "dieciocho", "diecinueve", "veinte", "veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve"}
+	spanishTens     = []string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+	spanishHundreds = []string{"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos", "seiscientos", "setecientos", "ochocientos", "novecientos"}
+)
+
+func spanishNumber(number int) string {
+	if number == 100 {
+		return "cien"
+	}
+
+	var parts []string
+	if number >= 100 {
+		parts = append(parts, spanishHundreds[number/100])
+		number %= 100
+	}
+
+	switch {
+	case number == 0:
+	case number < 30:
+		parts = append(parts, spanishOnes[number])
+	case number%10 == 0:
+		parts = append(parts, spanishTens[number/10])
+	default:
+		parts = append(parts, spanishTens[number/10]+" y "+spanishOnes[number%10])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+var (
+	frenchOnes = []string{"", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf", "dix", "onze", "douze", "treize", "quatorze", "quinze", "seize", "dix-sept", "dix-huit", "dix-neuf"}
+	frenchTens = []string{"", "", "vingt", "trente", "quarante", "cinquante", "soixante", "soixante", "quatre-vingt", "quatre-vingt"}
+)
+
+func frenchNumber(number int) string {
+	var parts []string
+	if number >= 100 {
+		hundreds := number / 100
+		number %= 100
+
+		switch {
+		case hundreds == 1:
+			parts = append(parts, "cent")
+		case number == 0:
+			parts = append(parts, frenchOnes[hundreds]+" cents")
+		default:
+			parts = append(parts, frenchOnes[hundreds]+" cent")
+		}
+	}
+
+	tens, ones := number/10, number%10
+	switch {
+	case number == 0:
+	case number < 20:
+		parts = append(parts, frenchOnes[number])
+	case tens == 7 || tens == 9:
+		// 70-79 and 90-99 are counted as 60+10-19 and 80+10-19
+		rest := frenchOnes[10+ones]
+		if tens == 7 && ones == 1 {
+			parts = append(parts, frenchTens[tens]+" et "+rest)
+		} else {
+			parts = append(parts, frenchTens[tens]+"-"+rest)
+		}
+	case ones == 0 && tens == 8:
+		parts = append(parts, "quatre-vingts")
+	case ones == 0:
+		parts = append(parts, frenchTens[tens])
+	case ones == 1 && tens != 8:
+		parts = append(parts, frenchTens[tens]+" et un")
+	default:
+		parts = append(parts, frenchTens[tens]+"-"+frenchOnes[ones])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+var (
+	germanOnes = []string{"", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun", "zehn", "elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn"}
+	germanTens = []string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig", "sechzig", "siebzig", "achtzig", "neunzig"}
+)
+
+func germanNumber(number int) string {
+	var b strings.Builder
+	if number >= 100 {
+		hundreds := number / 100
+		if hundreds == 1 {
+			b.WriteString("ein")
+		} else {
+			b.WriteString(germanOnes[hundreds])
+		}
+		b.WriteString("hundert")
+		number %= 100
+	}
+
+	tens, ones := number/10, number%10
+	switch {
+	case number == 0:
+	case number < 20:
+		b.WriteString(germanOnes[number])
+	case ones == 0:
+		b.WriteString(germanTens[tens])
+	default:
+		one := germanOnes[ones]
+		if ones == 1 {
+			one = "ein"
+		}
+		b.WriteString(one + "und" + germanTens[tens])
+	}
+
+	return b.String()
+}
@@ -9,24 +9,109 @@ var (
 	ErrProfileMissingName = errors.New("profile: missing Name")
 )
 
+type ErrProfileUnknownID struct {
+	ID string
+}
+
+func (e ErrProfileUnknownID) Error() string {
+	return fmt.Sprintf("profile: ID \"%s\" is not defined in profiles.yml or the profiles_registry of pub.yml", e.ID)
+}
+
 // Profile may represent an individual or an organization that is credited as either an author, contributor or publisher affliated with a [Book].
+//
+// A profile may reference a profiles.yml entry by ID, as a plain string (e.g.
+// "jane-doe") or as a mapping whose other fields override the entry.
 type Profile struct {
+	ID             string      `json:"id"`
 	Name           string      `json:"name"`
 	NamesAlternate []string    `json:"names_alternate"`
+	Role           Role        `json:"role"`
 	Content        Content     `json:"content"`
 	External       []Reference `json:"external"`
 }
 
-func (p *Profile) EnsureValid() error {
+// UnmarshalYAML decodes either a profile ID (as a plain string) or a full profile.
+func (p *Profile) UnmarshalYAML(unmarshal func(any) error) error {
+	var id string
+	if err := unmarshal(&id); err == nil {
+		*p = Profile{ID: id}
+		return nil
+	}
+
+	type profile Profile
+	return unmarshal((*profile)(p))
+}
+
+// resolve fills in the empty fields of the profile from its ID's entry in registry.
+func (p *Profile) resolve(registry map[string]Profile) {
+	if p.ID == "" {
+		return
+	}
+
+	base, ok := registry[p.ID]
+	if !ok {
+		return
+	}
+
 	if p.Name == "" {
-		return ErrProfileMissingName
+		p.Name = base.Name
 	}
 
-	for _, e := range p.External {
-		if err := e.EnsureValid(); err != nil {
-			return fmt.Errorf("profile \"%s\": %w", p.Name, err)
+	if len(p.NamesAlternate) == 0 {
+		p.NamesAlternate = base.NamesAlternate
+	}
+
+	if p.Role == "" {
+		p.Role = base.Role
+	}
+
+	if len(p.Content.Raw) == 0 {
+		p.Content = base.Content
+	}
+
+	if len(p.External) == 0 {
+		p.External = base.External
+	}
+}
+
+// resolveProfiles resolves the profiles of the book and its chapters.
+func (b *Book) resolveProfiles() {
+	resolve := func(profiles []Profile) {
+		for i := range profiles {
+			profiles[i].resolve(b.ProfilesRegistry)
 		}
 	}
 
-	return nil
+	resolve(b.Authors)
+	resolve(b.Contributors)
+	resolve(b.Publishers)
+
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		resolve(chapter.Authors)
+		resolve(chapter.Contributors)
+		resolve(chapter.Publishers)
+	}
+}
+
+func (p *Profile) EnsureValid() error {
+	return validateWith(func(v *validator) {
+		p.validate(v, nil, nil)
+	})
+}
+
+// validate checks the profile, whose ID (if any) must be defined in registry.
+func (p *Profile) validate(v *validator, loc location, registry map[string]Profile) {
+	if _, ok := registry[p.ID]; p.ID != "" && !ok {
+		v.error(loc.field("id"), ErrProfileUnknownID{ID: p.ID})
+		return
+	}
+
+	if p.Name == "" {
+		v.error(loc.field("name"), ErrProfileMissingName)
+	}
+
+	for i := range p.External {
+		e := &p.External[i]
+		e.validate(v, loc.index("external", i), fmt.Sprintf("profile \"%s\": ", p.Name))
+	}
 }
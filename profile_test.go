@@ -0,0 +1,134 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRoleUnmarshalText(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Role
+		wantErr bool
+	}{
+		{"trl", RoleTranslator, false},
+		{"translator", RoleTranslator, false},
+		{" Translator ", RoleTranslator, false},
+		{"ILL", RoleIllustrator, false},
+		{"author of foreword", RoleAuthorOfForeword, false},
+		{"", "", false},
+		{"wizard", "", true},
+	}
+
+	for _, tt := range tests {
+		var got Role
+		err := got.UnmarshalText([]byte(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalText(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("UnmarshalText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestProfilesRegistry(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":         {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\nauthors:\n  - jane\ncontributors:\n  - id: sam\n    role: illustrator\n")},
+		"book/profiles.yml":    {Data: []byte("jane:\n  name: Jane Doe\nsam:\n  name: Sam Roe\n  role: translator\n")},
+		"book/nav.yml":         {Data: []byte("- content_file_name: one.md\n  authors:\n    - sam\n")},
+		"book/chapters/one.md": {Data: []byte("One\n")},
+	}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  Profile
+		want Profile
+	}{
+		{"by ID", book.Authors[0], Profile{ID: "jane", Name: "Jane Doe"}},
+		{"role kept", book.Contributors[0], Profile{ID: "sam", Name: "Sam Roe", Role: RoleIllustrator}},
+		{"chapter", book.Chapters[0].Authors[0], Profile{ID: "sam", Name: "Sam Roe", Role: RoleTranslator}},
+		{"registry ID", *book.ProfileByID("jane"), Profile{ID: "jane", Name: "Jane Doe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("profile = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfilesRegistryUnknownID(t *testing.T) {
+	fsys := fstest.MapFS{
+		"book/pub.yml":      {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\nauthors:\n  - john\n")},
+		"book/profiles.yml": {Data: []byte("jane:\n  name: Jane Doe\n")},
+	}
+
+	_, err := NewBookFS(fsys, "book")
+
+	var unknown ErrProfileUnknownID
+	if !errors.As(err, &unknown) || unknown.ID != "john" {
+		t.Errorf("NewBookFS() error = %v, want ErrProfileUnknownID for \"john\"", err)
+	}
+}
+
+func TestEnsureValidDoesNotChangeBook(t *testing.T) {
+	book := Book{
+		UniqueID:         "  My-Book ",
+		LanguageCode:     "en",
+		TimeZone:         "America/Toronto",
+		ProfilesRegistry: map[string]Profile{"jane": {Name: "Jane Doe"}},
+		Authors:          []Profile{{ID: "jane"}},
+		Copyright:        Copyright{Licenses: []License{{SPDX: "cc-by-4.0"}}},
+		Chapters:         []Chapter{{ContentFileName: "One.md"}},
+	}
+	book.Chapters[0].Book = &book
+
+	before := Book{
+		UniqueID:         book.UniqueID,
+		LanguageCode:     book.LanguageCode,
+		TimeZone:         book.TimeZone,
+		ProfilesRegistry: map[string]Profile{"jane": {Name: "Jane Doe"}},
+		Authors:          []Profile{{ID: "jane"}},
+		Copyright:        Copyright{Licenses: []License{{SPDX: "cc-by-4.0"}}},
+	}
+
+	// the book is not normalized yet, so its missing title is reported but not fixed
+	if err := book.EnsureValid(); err == nil {
+		t.Fatalf("EnsureValid() error = nil, want an error")
+	}
+
+	if book.UniqueID != before.UniqueID || book.Title != "" {
+		t.Errorf("EnsureValid() changed UniqueID, Title to %q, %q", book.UniqueID, book.Title)
+	}
+	if !reflect.DeepEqual(book.ProfilesRegistry, before.ProfilesRegistry) || !reflect.DeepEqual(book.Authors, before.Authors) {
+		t.Errorf("EnsureValid() changed profiles to %+v, %+v", book.ProfilesRegistry, book.Authors)
+	}
+	if !reflect.DeepEqual(book.Copyright, before.Copyright) {
+		t.Errorf("EnsureValid() changed copyright to %+v", book.Copyright)
+	}
+	if chapter := book.Chapters[0]; chapter.UniqueID != "" || chapter.Title != "" {
+		t.Errorf("EnsureValid() changed chapter UniqueID, Title to %q, %q", chapter.UniqueID, chapter.Title)
+	}
+
+	book.normalize()
+	if book.UniqueID != "my-book" || book.Title != "my-book" || book.Chapters[0].UniqueID != "one" {
+		t.Errorf("normalize() UniqueID, Title, chapter UniqueID = %q, %q, %q", book.UniqueID, book.Title, book.Chapters[0].UniqueID)
+	}
+	if book.Authors[0].Name != "Jane Doe" || book.Copyright.Licenses[0].SPDX != "CC-BY-4.0" {
+		t.Errorf("normalize() author, license = %+v, %+v", book.Authors[0], book.Copyright.Licenses[0])
+	}
+	if err := book.EnsureValid(); err != nil {
+		t.Errorf("EnsureValid() after normalize() error = %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package pub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	BookReadingOrdersConfigFileName = "reading_orders.yml"
+
+	// ReadingOrderDefault is the ID of the order of the chapters in nav.yml.
+	ReadingOrderDefault = "default"
+)
+
+var (
+	ErrReadingOrderMissingID  = errors.New("reading order: missing id")
+	ErrReadingOrderNoChapters = errors.New("reading order: no chapters (list the unique IDs of the chapters under \"chapters\", in reading order)")
+)
+
+type ErrReadingOrderDuplicateID struct {
+	ID string
+}
+
+func (e ErrReadingOrderDuplicateID) Error() string {
+	return fmt.Sprintf("reading order: id \"%s\" is used by more than one reading order", e.ID)
+}
+
+type ErrReadingOrderSlugUnsafe struct {
+	ID string
+}
+
+func (e ErrReadingOrderSlugUnsafe) Error() string {
+	return fmt.Sprintf("reading order: id \"%s\" cannot name the reading order's page (it must contain a letter or digit and must not start with \".\")", e.ID)
+}
+
+type ErrReadingOrderDuplicateSlug struct {
+	ID    string
+	Other string
+	Slug  string
+}
+
+func (e ErrReadingOrderDuplicateSlug) Error() string {
+	return fmt.Sprintf("reading order: ids \"%s\" and \"%s\" would both be written to the page \"%s\" (give them ids that differ in more than case and punctuation)", e.Other, e.ID, e.Slug)
+}
+
+type ErrReadingOrderReservedID struct {
+	ID string
+}
+
+func (e ErrReadingOrderReservedID) Error() string {
+	return fmt.Sprintf("reading order: id \"%s\" is reserved for the order of %s", e.ID, BookChaptersConfigFileName)
+}
+
+type ErrReadingOrderChapterNotFound struct {
+	Order    string
+	UniqueID string
+}
+
+func (e ErrReadingOrderChapterNotFound) Error() string {
+	return fmt.Sprintf("reading order \"%s\": no chapter has the unique ID \"%s\"", e.Order, e.UniqueID)
+}
+
+type ErrReadingOrderDuplicateChapter struct {
+	Order    string
+	UniqueID string
+}
+
+func (e ErrReadingOrderDuplicateChapter) Error() string {
+	return fmt.Sprintf("reading order \"%s\": chapter \"%s\" is listed more than once", e.Order, e.UniqueID)
+}
+
+type ErrReadingOrderChapterUnreadable struct {
+	Order    string
+	UniqueID string
+}
+
+func (e ErrReadingOrderChapterUnreadable) Error() string {
+	return fmt.Sprintf("reading order \"%s\": chapter \"%s\" is a placeholder, part or volume, and is left out of the reading order", e.Order, e.UniqueID)
+}
+
+// ReadingOrder is a named order to read the chapters of a [Book] in, besides the
+// order of nav.yml (e.g. a chronological order).
+//
+// Reading orders are defined under "reading_orders" of nav.yml, or in
+// reading_orders.yml.
+type ReadingOrder struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	ChapterIDs  []string `json:"chapters"`
+
+	// Chapters are the readable chapters of the book listed by ChapterIDs.
+	Chapters []*Chapter `json:"-"`
+	Slug     string     `json:"-"`
+
+	loc location
+}
+
+// chapterLinks are the previous and next chapters of a chapter in a reading order.
+type chapterLinks struct {
+	previous *Chapter
+	next     *Chapter
+}
+
+// PreviousIn returns the chapter before this one in the reading order with the
+// given ID, or nil.
+func (c Chapter) PreviousIn(order string) *Chapter {
+	if order == ReadingOrderDefault {
+		return c.Previous
+	}
+
+	return c.readingOrders[order].previous
+}
+
+// NextIn returns the chapter after this one in the reading order with the given
+// ID, or nil.
+func (c Chapter) NextIn(order string) *Chapter {
+	if order == ReadingOrderDefault {
+		return c.Next
+	}
+
+	return c.readingOrders[order].next
+}
+
+// InReadingOrder reports whether the chapter is part of the reading order with the given ID.
+func (c Chapter) InReadingOrder(order string) bool {
+	if order == ReadingOrderDefault {
+		return !c.IsPlaceholder() && !c.IsDivision() && !c.InSplitVolume()
+	}
+
+	_, ok := c.readingOrders[order]
+
+	return ok
+}
+
+// ReadingOrderByID returns the reading order with the given ID, or nil.
+func (b Book) ReadingOrderByID(id string) *ReadingOrder {
+	for i := range b.ReadingOrders {
+		if b.ReadingOrders[i].ID == id {
+			return &b.ReadingOrders[i]
+		}
+	}
+
+	return nil
+}
+
+// normalize trims the reading order's ID, and fills in its title and slug.
+func (o *ReadingOrder) normalize() {
+	o.ID = strings.TrimSpace(o.ID)
+	if o.Title == "" {
+		o.Title = o.ID
+	}
+	o.Slug = slugify(o.ID)
+
+	for i, id := range o.ChapterIDs {
+		o.ChapterIDs[i] = strings.ToLower(strings.TrimSpace(id))
+	}
+}
+
+func (o ReadingOrder) validate(v *validator, chapters map[string]*Chapter) {
+	loc := o.loc
+
+	if o.ID == "" {
+		v.error(loc.field("id"), ErrReadingOrderMissingID)
+	}
+
+	if strings.EqualFold(o.ID, ReadingOrderDefault) {
+		v.error(loc.field("id"), ErrReadingOrderReservedID{ID: o.ID})
+	}
+
+	if len(o.ChapterIDs) == 0 {
+		v.error(loc.field("chapters"), ErrReadingOrderNoChapters)
+	}
+
+	seen := make(map[string]bool)
+	for i, id := range o.ChapterIDs {
+		chapter, ok := chapters[id]
+		switch {
+		case !ok:
+			v.error(loc.index("chapters", i), ErrReadingOrderChapterNotFound{Order: o.ID, UniqueID: id})
+		case seen[id]:
+			v.error(loc.index("chapters", i), ErrReadingOrderDuplicateChapter{Order: o.ID, UniqueID: id})
+		case chapter.IsPlaceholder() || chapter.IsDivision():
+			v.warning(loc.index("chapters", i), ErrReadingOrderChapterUnreadable{Order: o.ID, UniqueID: id})
+		}
+		seen[id] = true
+	}
+}
+
+// linkReadingOrders resolves the chapters of each reading order of the book, and
+// links them in the order.
+func (b *Book) linkReadingOrders() {
+	chapters := make(map[string]*Chapter)
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		chapter.readingOrders = nil
+		chapters[chapter.UniqueID] = chapter
+	}
+
+	for i := range b.ReadingOrders {
+		order := &b.ReadingOrders[i]
+
+		order.Chapters = nil
+		for _, id := range order.ChapterIDs {
+			chapter, ok := chapters[id]
+			if !ok || chapter.IsPlaceholder() || chapter.IsDivision() || chapter.InSplitVolume() {
+				continue
+			}
+
+			order.Chapters = append(order.Chapters, chapter)
+		}
+
+		for j, chapter := range order.Chapters {
+			var links chapterLinks
+			if j-1 >= 0 {
+				links.previous = order.Chapters[j-1]
+			}
+
+			if j+1 < len(order.Chapters) {
+				links.next = order.Chapters[j+1]
+			}
+
+			if chapter.readingOrders == nil {
+				chapter.readingOrders = make(map[string]chapterLinks)
+			}
+			chapter.readingOrders[order.ID] = links
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// readingOrdersFS returns a file system with a book at "book" of the orders.
+func readingOrdersFS(orders string) fstest.MapFS {
+	return fstest.MapFS{
+		"book/pub.yml":           {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+		"book/nav.yml":           {Data: []byte("chapters:\n  - content_file_name: one.md\n  - content_file_name: two.md\n  - content_file_name: three.md\n    state: placeholder\nreading_orders:\n" + orders)},
+		"book/chapters/one.md":   {Data: []byte("One\n")},
+		"book/chapters/two.md":   {Data: []byte("Two\n")},
+		"book/chapters/three.md": {Data: []byte("")},
+	}
+}
+
+func TestReadingOrders(t *testing.T) {
+	fsys := readingOrdersFS("  - id: Reverse\n    chapters: [two, One]\n")
+	fsys["book/reading_orders.yml"] = &fstest.MapFile{Data: []byte("- id: two-only\n  title: Two only\n  chapters: [two]\n")}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	one, two := &book.Chapters[0], &book.Chapters[1]
+	tests := []struct {
+		name     string
+		order    string
+		chapter  *Chapter
+		previous *Chapter
+		next     *Chapter
+		in       bool
+	}{
+		{"default first", ReadingOrderDefault, one, nil, two, true},
+		{"default last", ReadingOrderDefault, two, one, nil, true},
+		{"reverse first", "Reverse", two, nil, one, true},
+		{"reverse last", "Reverse", one, two, nil, true},
+		{"single chapter", "two-only", two, nil, nil, true},
+		{"not in order", "two-only", one, nil, nil, false},
+		{"unknown order", "missing", one, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.chapter.PreviousIn(tt.order); got != tt.previous {
+				t.Errorf("PreviousIn(%q) = %v, want %v", tt.order, got, tt.previous)
+			}
+			if got := tt.chapter.NextIn(tt.order); got != tt.next {
+				t.Errorf("NextIn(%q) = %v, want %v", tt.order, got, tt.next)
+			}
+			if got := tt.chapter.InReadingOrder(tt.order); got != tt.in {
+				t.Errorf("InReadingOrder(%q) = %t, want %t", tt.order, got, tt.in)
+			}
+		})
+	}
+
+	reverse := book.ReadingOrderByID("Reverse")
+	if reverse == nil || reverse.Title != "Reverse" || reverse.Slug != "reverse" || !reflect.DeepEqual(reverse.ChapterIDs, []string{"two", "one"}) {
+		t.Errorf("ReadingOrderByID(\"Reverse\") = %+v", reverse)
+	}
+	if book.ReadingOrderByID(ReadingOrderDefault) != nil {
+		t.Errorf("ReadingOrderByID(%q) != nil", ReadingOrderDefault)
+	}
+}
+
+func TestReadingOrdersErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders string
+		want   error
+	}{
+		{"missing id", "  - chapters: [one]\n", ErrReadingOrderMissingID},
+		{"reserved id", "  - id: Default\n    chapters: [one]\n", ErrReadingOrderReservedID{}},
+		{"no chapters", "  - id: empty\n", ErrReadingOrderNoChapters},
+		{"unknown chapter", "  - id: order\n    chapters: [four]\n", ErrReadingOrderChapterNotFound{}},
+		{"duplicate chapter", "  - id: order\n    chapters: [one, One]\n", ErrReadingOrderDuplicateChapter{}},
+		{"duplicate id", "  - id: order\n    chapters: [one]\n  - id: order\n    chapters: [two]\n", ErrReadingOrderDuplicateID{}},
+		{"unsafe slug", "  - id: .hidden\n    chapters: [one]\n", ErrReadingOrderSlugUnsafe{}},
+		{"duplicate slug", "  - id: by date\n    chapters: [one]\n  - id: by-date\n    chapters: [two]\n", ErrReadingOrderDuplicateSlug{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(readingOrdersFS(tt.orders), "book")
+
+			found := errors.Is(err, tt.want)
+			if reflect.TypeOf(tt.want).Kind() == reflect.Struct {
+				found = errors.As(err, reflect.New(reflect.TypeOf(tt.want)).Interface())
+			}
+			if !found {
+				t.Errorf("NewBookFS() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingOrderUnreadableChapter(t *testing.T) {
+	book, err := NewBookFS(readingOrdersFS("  - id: order\n    chapters: [three, one]\n"), "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	var unreadable ErrReadingOrderChapterUnreadable
+	if warnings := book.Diagnostics().Warnings(); !errors.As(warnings, &unreadable) || unreadable.UniqueID != "three" {
+		t.Errorf("Diagnostics().Warnings() = %v, want ErrReadingOrderChapterUnreadable for \"three\"", warnings)
+	}
+
+	order := book.ReadingOrderByID("order")
+	if len(order.Chapters) != 1 || order.Chapters[0] != &book.Chapters[0] {
+		t.Errorf("Chapters = %v, want only chapter one", order.Chapters)
+	}
+}
@@ -35,31 +35,34 @@ type Reference struct {
 }
 
 func (r *Reference) EnsureValid() error {
-	return r.checkValid(r.Name, 0)
+	return validateWith(func(v *validator) {
+		r.validate(v, nil, "")
+	})
 }
 
-// Level starts at 0. Can only nest up to 2 levels (level either 0 or 1 or it returns err)
-func (r *Reference) checkValid(topLevelName string, level int) error {
+// validate checks the reference, prefixing any error messages with prefix.
+func (r *Reference) validate(v *validator, loc location, prefix string) {
+	r.checkValid(v, loc, prefix, r.Name, 0)
+}
+
+// Level starts at 0. Can only nest up to 2 levels (level either 0 or 1 or it records an error)
+func (r *Reference) checkValid(v *validator, loc location, prefix, topLevelName string, level int) {
 	if r.Name == "" && r.Address != "" {
 		r.Name = r.Address
 	}
 
 	if r.Name == "" {
-		return ErrReferenceMissingName
-	}
-
-	if r.Address == "" {
-		return ErrReferenceMissingAddress{Name: r.Name}
+		v.error(loc.field("name"), prefixedError(prefix, ErrReferenceMissingName))
+	} else if r.Address == "" {
+		v.error(loc.field("address"), prefixedError(prefix, ErrReferenceMissingAddress{Name: r.Name}))
 	}
 
 	if level > 1 {
-		return ErrReferenceTooManyNestedDomains{TopLevelName: topLevelName, DomainAlternateName: r.Name}
+		v.error(loc, prefixedError(prefix, ErrReferenceTooManyNestedDomains{TopLevelName: topLevelName, DomainAlternateName: r.Name}))
+		return
 	}
-	for _, alt := range r.DomainsAlternate {
-		if err := alt.checkValid(topLevelName, level+1); err != nil {
-			return err
-		}
+	for i := range r.DomainsAlternate {
+		alt := &r.DomainsAlternate[i]
+		alt.checkValid(v, loc.index("domains_alternate", i), prefix, topLevelName, level+1)
 	}
-
-	return nil
 }
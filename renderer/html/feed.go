@@ -0,0 +1,216 @@
+package html
+
+import (
+	"encoding/xml"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JessebotX/pub"
+)
+
+// FeedFileName is the Atom feed of a book's chapters in its output directory.
+// It is only written for books with a URL, as feed links must be absolute.
+const FeedFileName = "feed.xml"
+
+const (
+	atomNamespace  = "http://www.w3.org/2005/Atom"
+	mediaNamespace = "http://search.yahoo.com/mrss/"
+)
+
+type atomFeed struct {
+	XMLName    xml.Name       `xml:"feed"`
+	Namespace  string         `xml:"xmlns,attr"`
+	MediaNS    string         `xml:"xmlns:media,attr"`
+	Lang       string         `xml:"xml:lang,attr,omitempty"`
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Subtitle   string         `xml:"subtitle,omitempty"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Rating     *mediaRating   `xml:"media:rating"`
+	Entries    []atomEntry    `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Rating     *mediaRating   `xml:"media:rating"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type mediaRating struct {
+	Scheme string `xml:"scheme,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// writeFeedToStaticSite writes the Atom feed of book to outputPath, newest
+// chapter first. Content warnings are categories, and ratings Media RSS ratings.
+func writeFeedToStaticSite(book *pub.Book, outputPath string) error {
+	base := bookURL(book)
+	if base == "" {
+		return nil
+	}
+
+	feed := atomFeed{
+		Namespace:  atomNamespace,
+		MediaNS:    mediaNamespace,
+		Lang:       book.LanguageCode,
+		ID:         base,
+		Title:      book.Title,
+		Subtitle:   book.Subtitle,
+		Links:      []atomLink{{Rel: "self", Type: "application/atom+xml", Href: base + FeedFileName}, {Rel: "alternate", Type: "text/html", Href: base}},
+		Authors:    atomPeople(book.Authors),
+		Categories: atomCategories(book.ContentWarningTerms()),
+		Rating:     newMediaRating(book.RatingTerm()),
+	}
+
+	var updated time.Time
+	for _, date := range []*pub.DateTime{book.DatePublishedStart, book.DatePublishedEnd} {
+		if date != nil && date.After(updated) {
+			updated = date.Time
+		}
+	}
+
+	for _, chapter := range slices.Backward(book.ChaptersAndSubchapters()) {
+		if !chapter.InReadingOrder(pub.ReadingOrderDefault) {
+			continue
+		}
+
+		link := base + "chapters/" + url.PathEscape(chapter.UniqueID) + ".html"
+		entry := atomEntry{
+			ID:         link,
+			Title:      chapter.Title,
+			Links:      []atomLink{{Rel: "alternate", Type: "text/html", Href: link}},
+			Authors:    atomPeople(chapter.Authors),
+			Categories: atomCategories(chapter.ContentWarningTerms()),
+			Rating:     newMediaRating(chapter.RatingTerm()),
+		}
+
+		if chapter.DatePublished != nil {
+			entry.Published = chapter.DatePublished.Format(time.RFC3339)
+		}
+
+		if date := firstDate(chapter.DateUpdated, chapter.DatePublished); date != nil {
+			entry.Updated = date.Format(time.RFC3339)
+			if date.After(updated) {
+				updated = date.Time
+			}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	// without any dates, the feed was last updated by this build
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+	for i := range feed.Entries {
+		if feed.Entries[i].Updated == "" {
+			feed.Entries[i].Updated = feed.Updated
+		}
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outputPath, append([]byte(xml.Header), append(data, '\n')...), defaultFilePerms)
+}
+
+// bookURL returns the URL of book's output directory with a trailing slash,
+// or "" if the book has no URL.
+func bookURL(book *pub.Book) string {
+	if book.ParentBook != nil {
+		parent := bookURL(book.ParentBook)
+		if parent == "" {
+			return ""
+		}
+
+		return parent + VolumesDirName + "/" + url.PathEscape(book.Slug()) + "/"
+	}
+
+	if book.URL == "" {
+		return ""
+	}
+
+	base := strings.TrimSuffix(book.URL, "/") + "/"
+	for _, language := range book.Languages {
+		if language.Current && !language.Original {
+			base += url.PathEscape(book.LanguageCode) + "/"
+		}
+	}
+
+	return base
+}
+
+// firstDate returns the first of dates that is not nil.
+func firstDate(dates ...*pub.DateTime) *pub.DateTime {
+	for _, date := range dates {
+		if date != nil {
+			return date
+		}
+	}
+
+	return nil
+}
+
+func atomPeople(profiles []pub.Profile) []atomPerson {
+	var people []atomPerson
+	for _, profile := range profiles {
+		if profile.Name != "" {
+			people = append(people, atomPerson{Name: profile.Name})
+		}
+	}
+
+	return people
+}
+
+// atomCategories returns a category for each of the content warnings terms.
+func atomCategories(terms []pub.AdvisoryTerm) []atomCategory {
+	var categories []atomCategory
+	for _, term := range terms {
+		categories = append(categories, atomCategory{Term: term.ID, Label: term.Name})
+	}
+
+	return categories
+}
+
+// newMediaRating maps a rating to the "urn:simple" scheme by its MinimumAge.
+// Ratings without a MinimumAge cannot be mapped, and are left out.
+func newMediaRating(term *pub.AdvisoryTerm) *mediaRating {
+	if term == nil || term.MinimumAge <= 0 {
+		return nil
+	}
+
+	if term.MinimumAge >= 18 {
+		return &mediaRating{Scheme: "urn:simple", Value: "adult"}
+	}
+
+	return &mediaRating{Scheme: "urn:simple", Value: "nonadult"}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
-	"strings"
 
 	"github.com/JessebotX/pub"
 
@@ -18,6 +18,41 @@ import (
 	mdhtml "github.com/yuin/goldmark/renderer/html"
 )
 
+const (
+	// VolumesDirName is the output directory of split volumes, each named after
+	// its [pub.Book.Slug].
+	VolumesDirName = "volumes"
+
+	// ReadingOrdersDirName is the output directory of the reading orders.
+	ReadingOrdersDirName = "orders"
+
+	// TaxonomiesDirName is the output directory of the taxonomies and their terms.
+	TaxonomiesDirName = "taxonomies"
+)
+
+// Paths of the templates in the root of the layouts, which are never copied into the output.
+const (
+	tplName             = "index.html"
+	chapterTplName      = "_chapter/index.html"
+	licenseTplName      = "_license/index.html"
+	partTplName         = "_part/index.html"
+	readingOrderTplName = "_reading_order/index.html"
+	taxonomyTplName     = "_taxonomy/index.html"
+	termTplName         = "_taxonomy/term.html"
+)
+
+// layoutTemplates are the templates of the layouts, which are not copied.
+var layoutTemplates = []string{
+	tplName,
+	chapterTplName,
+	licenseTplName,
+	partTplName,
+	readingOrderTplName,
+	taxonomyTplName,
+	termTplName,
+	LibraryLayoutsDirName,
+}
+
 const (
 	defaultFilePerms = 0666
 	defaultDirPerms  = 0755
@@ -40,7 +75,15 @@ var (
 	)
 )
 
+// RenderBook renders book into outputDir with the layouts in layoutsDir.
+// See [RenderBookFS].
 func RenderBook(book *pub.Book, inputDir, outputDir, layoutsDir string) error {
+	return RenderBookFS(book, inputDir, outputDir, os.DirFS(layoutsDir))
+}
+
+// RenderBookFS renders book into outputDir with the layouts in layouts, and each
+// translation into a directory named after its language code.
+func RenderBookFS(book *pub.Book, inputDir, outputDir string, layouts fs.FS) error {
 	if err := os.MkdirAll(outputDir, defaultDirPerms); err != nil {
 		return fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err)
 	}
@@ -53,23 +96,68 @@ func RenderBook(book *pub.Book, inputDir, outputDir, layoutsDir string) error {
 	book.Content.AddFormat("html", parsedHTML)
 
 	// --- Templates ---
-	tplName := "index.html"
-	tpl, err := template.New("index.html").Funcs(TplFuncs).ParseFiles(filepath.Join(layoutsDir, tplName))
+	tpl, err := template.New("index.html").Funcs(TplFuncs).ParseFS(layouts, tplName)
+	if err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	chapterTpl, err := template.New("index.html").Funcs(TplFuncs).ParseFS(layouts, chapterTplName)
+	if err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	licenseTpl := template.New("index.html").Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, licenseTplName); err == nil {
+		licenseTpl, err = licenseTpl.ParseFS(layouts, licenseTplName)
+	} else {
+		licenseTpl, err = licenseTpl.Parse(defaultLicenseTemplate)
+	}
+	if err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	partTpl := template.New("index.html").Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, partTplName); err == nil {
+		partTpl, err = partTpl.ParseFS(layouts, partTplName)
+	} else {
+		partTpl, err = partTpl.Parse(defaultPartTemplate)
+	}
+	if err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	readingOrderTpl := template.New("index.html").Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, readingOrderTplName); err == nil {
+		readingOrderTpl, err = readingOrderTpl.ParseFS(layouts, readingOrderTplName)
+	} else {
+		readingOrderTpl, err = readingOrderTpl.Parse(defaultReadingOrderTemplate)
+	}
 	if err != nil {
 		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
 	}
 
-	chapterTplName := filepath.Join("_chapter", "index.html")
-	chapterTpl, err := template.New("index.html").Funcs(TplFuncs).ParseFiles(filepath.Join(layoutsDir, chapterTplName))
+	taxonomyTpl := template.New("index.html").Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, taxonomyTplName); err == nil {
+		taxonomyTpl, err = taxonomyTpl.ParseFS(layouts, taxonomyTplName)
+	} else {
+		taxonomyTpl, err = taxonomyTpl.Parse(defaultTaxonomyTemplate)
+	}
+	if err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	termTpl := template.New("term.html").Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, termTplName); err == nil {
+		termTpl, err = termTpl.ParseFS(layouts, termTplName)
+	} else {
+		termTpl, err = termTpl.Parse(defaultTermTemplate)
+	}
 	if err != nil {
 		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
 	}
 
 	// --- Copy static layout files ---
-	if err := copyDirectory(layoutsDir, outputDir, []string{
-		tplName,
-		chapterTplName,
-	}); err != nil {
+	if err := copyDirectory(layouts, outputDir, layoutTemplates); err != nil {
 		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
 	}
 
@@ -91,21 +179,294 @@ func RenderBook(book *pub.Book, inputDir, outputDir, layoutsDir string) error {
 	}
 
 	for _, chapter := range book.ChaptersAndSubchapters() {
-		if err := writeChapterToStaticSite(chapter, chapter.InputPath, filepath.Join(chaptersDir, chapter.UniqueID+".html"), chapterTpl); err != nil {
+		// placeholders have no page, and split volumes are rendered as sub-books below
+		if chapter.IsPlaceholder() || chapter.InSplitVolume() {
+			continue
+		}
+
+		pageTpl := chapterTpl
+		if chapter.IsDivision() {
+			pageTpl = partTpl
+		}
+
+		if err := writeChapterToStaticSite(chapter, chapter.InputPath, filepath.Join(chaptersDir, chapter.UniqueID+".html"), pageTpl); err != nil {
+			return writeErrHTMLAndReturn(err, outputDir)
+		}
+	}
+
+	// --- Reading orders ---
+	if err := writeReadingOrdersToStaticSite(book, filepath.Join(outputDir, ReadingOrdersDirName), readingOrderTpl); err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	// --- Taxonomies ---
+	if err := writeTaxonomiesToStaticSite(book, filepath.Join(outputDir, TaxonomiesDirName), taxonomyTpl, termTpl); err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	// --- Feed ---
+	if err := writeFeedToStaticSite(book, filepath.Join(outputDir, FeedFileName)); err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE FEED] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	// --- Volumes ---
+	if book.SplitVolumes {
+		volumes, err := book.VolumeBooks()
+		if err != nil {
+			return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+		}
+
+		for _, volume := range volumes {
+			if err := RenderBookFS(volume, inputDir, filepath.Join(outputDir, VolumesDirName, volume.Slug()), layouts); err != nil {
+				return writeErrHTMLAndReturn(err, outputDir)
+			}
+		}
+	}
+
+	// --- Translations ---
+	for i := range book.Translations {
+		translation := &book.Translations[i]
+		if err := RenderBookFS(translation, inputDir, filepath.Join(outputDir, translation.LanguageCode), layouts); err != nil {
 			return writeErrHTMLAndReturn(err, outputDir)
 		}
 	}
 
+	// --- Licenses ---
+	if err := writeLicensesToStaticSite(book, filepath.Join(outputDir, "licenses"), licenseTpl); err != nil {
+		return writeErrHTMLAndReturn(fmt.Errorf("[WRITE BOOK] \"%s\": %w", inputDir, err), outputDir)
+	}
+
+	return nil
+}
+
+const defaultPartTemplate = `<!DOCTYPE html>
+<html lang="{{ .Book.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Title }} | {{ .Book.Title }}</title>
+</head>
+<body>
+	<h1>{{ with .Number }}{{ . }}. {{ end }}{{ .Title }}</h1>
+	{{ with .Subtitle }}<p>{{ . }}</p>{{ end }}
+	{{ .Content.Format "html" }}
+	{{ with .Chapters }}
+	<ol>
+		{{ range . }}
+		<li>{{ if .IsPlaceholder }}{{ .Title }}{{ else }}<a href="{{ .UniqueID }}.html">{{ .Title }}</a>{{ end }}</li>
+		{{ end }}
+	</ol>
+	{{ end }}
+</body>
+</html>
+`
+
+// ReadingOrderData is passed to the "_reading_order/index.html" template.
+type ReadingOrderData struct {
+	pub.ReadingOrder
+	Book *pub.Book
+}
+
+const defaultReadingOrderTemplate = `<!DOCTYPE html>
+<html lang="{{ .Book.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Title }} | {{ .Book.Title }}</title>
+</head>
+<body>
+	<h1>{{ .Title }}</h1>
+	{{ with .Description }}<p>{{ . }}</p>{{ end }}
+	<ol>
+		{{ range .Chapters }}
+		<li><a href="../chapters/{{ .UniqueID }}.html">{{ .Title }}</a></li>
+		{{ end }}
+	</ol>
+</body>
+</html>
+`
+
+// TaxonomyData is passed to the "_taxonomy/index.html" template.
+type TaxonomyData struct {
+	pub.Taxonomy
+	Book *pub.Book
+}
+
+// TermData is passed to the "_taxonomy/term.html" template.
+type TermData struct {
+	pub.TaxonomyTerm
+	Taxonomy *pub.Taxonomy
+	Book     *pub.Book
+}
+
+const defaultTaxonomyTemplate = `<!DOCTYPE html>
+<html lang="{{ .Book.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Name }} | {{ .Book.Title }}</title>
+</head>
+<body>
+	<h1>{{ .Name }}</h1>
+	{{ with .Description }}<p>{{ . }}</p>{{ end }}
+	<ul>
+		{{ range .Terms }}
+		<li><a href="{{ .Slug }}.html">{{ .Name }}</a> ({{ len .Chapters }})</li>
+		{{ end }}
+	</ul>
+</body>
+</html>
+`
+
+const defaultTermTemplate = `<!DOCTYPE html>
+<html lang="{{ .Book.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Name }} | {{ .Taxonomy.Name }} | {{ .Book.Title }}</title>
+</head>
+<body>
+	<p><a href="index.html">{{ .Taxonomy.Name }}</a></p>
+	<h1>{{ .Name }}</h1>
+	<ol>
+		{{ range .Chapters }}
+		<li><a href="../../chapters/{{ .UniqueID }}.html">{{ .Title }}</a></li>
+		{{ end }}
+	</ol>
+</body>
+</html>
+`
+
+// LicenseData is passed to the "_license/index.html" template for each license.
+type LicenseData struct {
+	pub.License
+	Book *pub.Book
+}
+
+const defaultLicenseTemplate = `<!DOCTYPE html>
+<html lang="{{ .Book.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ with .Name }}{{ . }}{{ else }}License{{ end }} | {{ .Book.Title }}</title>
+</head>
+<body>
+	<h1>{{ with .Name }}{{ . }}{{ else }}License{{ end }}</h1>
+	{{ with .URL }}<p><a href="{{ . }}">{{ . }}</a></p>{{ end }}
+	<pre>{{ .Text }}</pre>
+</body>
+</html>
+`
+
+// writeLicensesToStaticSite writes "<slug>.html" into outputDir for each distinct
+// license of book and its chapters.
+func writeLicensesToStaticSite(book *pub.Book, outputDir string, tpl *template.Template) error {
+	licenses := slices.Clone(book.Copyright.Licenses)
+	for _, chapter := range book.ChaptersAndSubchapters() {
+		licenses = append(licenses, chapter.Copyright.Licenses...)
+	}
+
+	if len(licenses) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(outputDir, defaultDirPerms); err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for _, license := range licenses {
+		slug := license.Slug()
+		if slug == "" || written[slug] {
+			continue
+		}
+		written[slug] = true
+
+		if err := writeLicenseToStaticSite(LicenseData{License: license, Book: book}, filepath.Join(outputDir, slug+".html"), tpl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeReadingOrdersToStaticSite writes "<slug>.html" for each reading order.
+func writeReadingOrdersToStaticSite(book *pub.Book, outputDir string, tpl *template.Template) error {
+	if len(book.ReadingOrders) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(outputDir, defaultDirPerms); err != nil {
+		return err
+	}
+
+	for _, order := range book.ReadingOrders {
+		if err := executeTemplateToFile(tpl, ReadingOrderData{ReadingOrder: order, Book: book}, filepath.Join(outputDir, order.Slug+".html")); err != nil {
+			return fmt.Errorf("[WRITE READING ORDER] \"%s\": %w", order.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// writeTaxonomiesToStaticSite writes "<slug>/index.html" for each taxonomy, and
+// "<slug>/<term slug>.html" for each of its terms.
+func writeTaxonomiesToStaticSite(book *pub.Book, outputDir string, taxonomyTpl, termTpl *template.Template) error {
+	for i := range book.Taxonomies {
+		taxonomy := &book.Taxonomies[i]
+
+		dir := filepath.Join(outputDir, taxonomy.Slug)
+		if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
+			return err
+		}
+
+		if err := executeTemplateToFile(taxonomyTpl, TaxonomyData{Taxonomy: *taxonomy, Book: book}, filepath.Join(dir, "index.html")); err != nil {
+			return fmt.Errorf("[WRITE TAXONOMY] \"%s\": %w", taxonomy.ID, err)
+		}
+
+		for _, term := range taxonomy.Terms {
+			if err := executeTemplateToFile(termTpl, TermData{TaxonomyTerm: term, Taxonomy: taxonomy, Book: book}, filepath.Join(dir, term.Slug+".html")); err != nil {
+				return fmt.Errorf("[WRITE TAXONOMY] \"%s\": term \"%s\": %w", taxonomy.ID, term.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeLicenseToStaticSite(data LicenseData, outputPath string, tpl *template.Template) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("[WRITE LICENSE] \"%s\": %w", data.Slug(), err)
+	}
+	defer f.Close()
+
+	if err := tpl.Execute(f, data); err != nil {
+		return fmt.Errorf("[WRITE LICENSE] \"%s\": %w", data.Slug(), err)
+	}
+
 	return nil
 }
 
 func writeChapterToStaticSite(chapter *pub.Chapter, inputPath, outputPath string, tpl *template.Template) error {
-	parsedHTML, err := convertMarkdownToHTML(chapter.Content.Raw)
+	raw, err := chapter.Content.Bytes()
+	if err != nil {
+		return fmt.Errorf("[WRITE CHAPTER] \"%s\": %w", inputPath, err)
+	}
+
+	parsedHTML, err := convertMarkdownToHTML(raw)
 	if err != nil {
 		return fmt.Errorf("[WRITE CHAPTER] \"%s\": %w", inputPath, err)
 	}
 	chapter.Content.AddFormat("html", parsedHTML)
 
+	if err := addAuthorsNotesHTML(chapter); err != nil {
+		return fmt.Errorf("[WRITE CHAPTER] \"%s\": %w", inputPath, err)
+	}
+
+	// keep memory use constant for books with many chapters, when their content is lazily loaded
+	defer chapter.Content.Release()
+
 	f, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("[WRITE CHAPTER] \"%s\": %w", inputPath, err)
@@ -129,49 +490,35 @@ func convertMarkdownToHTML(rawText []byte) (template.HTML, error) {
 }
 
 // Copy files, directories and subdirectories, and supports excluding certain files from copying
-func copyDirectory(sourcePath, destinationPath string, excludePaths []string) error {
-	return copyDirectoryHelper(sourcePath, destinationPath, sourcePath, excludePaths)
-}
-
-// Recursively copies directories and subdirectories. Should use the copyDirectory() function wrapper instead.
-func copyDirectoryHelper(curr, dest, start string, excludePaths []string) error {
-	items, err := os.ReadDir(curr)
-	if err != nil {
-		return err
-	}
-
-	for _, item := range items {
-		target := filepath.Join(curr, item.Name())
-		targetFromStart := strings.TrimLeft(strings.TrimPrefix(target, start), "/\\")
+func copyDirectory(source fs.FS, destinationPath string, excludePaths []string) error {
+	return fs.WalkDir(source, ".", func(target string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 
 		// check exclusions
-		if slices.Contains(excludePaths, targetFromStart) {
-			continue
+		if slices.Contains(excludePaths, target) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
 		}
 
-		newFilePath := filepath.Join(dest, targetFromStart)
-
-		// recursively copy subdirectories
-		if item.IsDir() {
-			if err := copyDirectoryHelper(target, dest, start, excludePaths); err != nil {
-				return err
-			}
-		} else {
-			if err := os.MkdirAll(newFilePath, defaultDirPerms); err != nil {
-				return err
-			}
+		if d.IsDir() {
+			return nil
+		}
 
-			if err := copyFile(target, newFilePath); err != nil {
-				return err
-			}
+		newFilePath := filepath.Join(destinationPath, filepath.FromSlash(target))
+		if err := os.MkdirAll(filepath.Dir(newFilePath), defaultDirPerms); err != nil {
+			return err
 		}
-	}
 
-	return nil
+		return copyFile(source, target, newFilePath)
+	})
 }
 
-func copyFile(sourcePath, destinationPath string) error {
-	in, err := os.Open(sourcePath)
+func copyFile(source fs.FS, sourcePath, destinationPath string) error {
+	in, err := source.Open(sourcePath)
 	if err != nil {
 		return err
 	}
@@ -226,3 +573,60 @@ func writeErrHTMLAndReturn(err error, outputDir string) error {
 
 	return err
 }
+
+// AddHTMLFormats adds the content of book and its translations in the "html" format.
+func AddHTMLFormats(book *pub.Book) error {
+	parsedHTML, err := convertMarkdownToHTML(book.Content.Raw)
+	if err != nil {
+		return fmt.Errorf("[BOOK] \"%s\": %w", book.InputPath, err)
+	}
+	book.Content.AddFormat("html", parsedHTML)
+
+	for _, chapter := range book.ChaptersAndSubchapters() {
+		raw, err := chapter.Content.Bytes()
+		if err != nil {
+			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+		}
+
+		parsedHTML, err := convertMarkdownToHTML(raw)
+		if err != nil {
+			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+		}
+		chapter.Content.AddFormat("html", parsedHTML)
+
+		if err := addAuthorsNotesHTML(chapter); err != nil {
+			return fmt.Errorf("[CHAPTER] \"%s\": %w", chapter.InputPath, err)
+		}
+	}
+
+	for i := range book.Translations {
+		if err := AddHTMLFormats(&book.Translations[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAuthorsNotesHTML adds the authors' notes of chapter in the "html" format.
+func addAuthorsNotesHTML(chapter *pub.Chapter) error {
+	include := chapter.Book == nil || chapter.Book.IncludesAuthorsNotes("html")
+
+	for _, note := range []*pub.Content{&chapter.AuthorsNotePrefix, &chapter.AuthorsNoteSuffix} {
+		var parsedHTML template.HTML
+		if include {
+			raw, err := note.Bytes()
+			if err != nil {
+				return err
+			}
+
+			parsedHTML, err = convertMarkdownToHTML(raw)
+			if err != nil {
+				return err
+			}
+		}
+		note.AddFormat("html", parsedHTML)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package html
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/JessebotX/pub"
+)
+
+const (
+	// LibraryLayoutsDirName is the layouts directory of the library pages.
+	LibraryLayoutsDirName = "_library"
+)
+
+// ListingData is passed to the listing templates of "_library" for each listing.
+type ListingData struct {
+	pub.Listing
+	Library *pub.Library
+}
+
+var defaultLibraryTemplates = map[string]string{
+	"index.html": `<!DOCTYPE html>
+<html lang="{{ .LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Title }}</title>
+</head>
+<body>
+	<h1>{{ .Title }}</h1>
+	{{ with .Description }}<p>{{ . }}</p>{{ end }}
+	<ul>
+	{{ range .Books }}
+		<li><a href="{{ .Slug }}/index.html">{{ .Title }}</a>{{ with .Authors }} by {{ range $i, $a := . }}{{ if $i }}, {{ end }}{{ $a.Name }}{{ end }}{{ end }}</li>
+	{{ end }}
+	</ul>
+	{{ with .TagListings }}<h2>Tags</h2>
+	<ul>{{ range . }}<li><a href="tags/{{ .Slug }}.html">{{ .Name }}</a> ({{ len .Books }})</li>{{ end }}</ul>{{ end }}
+	{{ with .AuthorListings }}<h2>Authors</h2>
+	<ul>{{ range . }}<li><a href="authors/{{ .Slug }}.html">{{ .Name }}</a> ({{ len .Books }})</li>{{ end }}</ul>{{ end }}
+	{{ with .SeriesListings }}<h2>Series</h2>
+	<ul>{{ range . }}<li><a href="series/{{ .Slug }}.html">{{ .Name }}</a> ({{ len .Books }})</li>{{ end }}</ul>{{ end }}
+</body>
+</html>
+`,
+	"listing.html": `<!DOCTYPE html>
+<html lang="{{ .Library.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Name }} | {{ .Library.Title }}</title>
+</head>
+<body>
+	<p><a href="../index.html">{{ .Library.Title }}</a></p>
+	<h1>{{ .Name }}</h1>
+	<ul>
+	{{ range .Books }}
+		<li><a href="../{{ .Slug }}/index.html">{{ .Title }}</a></li>
+	{{ end }}
+	</ul>
+</body>
+</html>
+`,
+	"series.html": `<!DOCTYPE html>
+<html lang="{{ .Library.LanguageCode }}">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Name }} | {{ .Library.Title }}</title>
+</head>
+<body>
+	<p><a href="../index.html">{{ .Library.Title }}</a></p>
+	<h1>{{ .Name }}</h1>
+	{{ with .Series }}
+		{{ with .Description }}<p>{{ . }}</p>{{ end }}
+		{{ .Content.Format "html" }}
+		<ul>
+		{{ range .Volumes }}
+			<li>{{ .NumberString }}. {{ with .Book }}<a href="../{{ .Slug }}/index.html">{{ .Title }}</a>{{ end }}</li>
+		{{ end }}
+		</ul>
+	{{ else }}
+		<ul>
+		{{ range .Books }}
+			<li><a href="../{{ .Slug }}/index.html">{{ .Title }}</a></li>
+		{{ end }}
+		</ul>
+	{{ end }}
+</body>
+</html>
+`,
+}
+
+// RenderLibrary renders library into outputDir with the layouts in layoutsDir.
+// See [RenderLibraryFS].
+func RenderLibrary(library *pub.Library, outputDir, layoutsDir string) error {
+	return RenderLibraryFS(library, outputDir, os.DirFS(layoutsDir))
+}
+
+// RenderLibraryFS renders each book of library into outputDir/<slug>, and the
+// library's pages from the "_library" templates of layouts, or built-in ones:
+//
+//   - "index.html": the catalog of every book, executed with the [pub.Library].
+//   - "tag.html", "author.html" and "series.html": a page for each listing,
+//     executed with [ListingData].
+func RenderLibraryFS(library *pub.Library, outputDir string, layouts fs.FS) error {
+	if err := os.MkdirAll(outputDir, defaultDirPerms); err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	for i := range library.Series {
+		series := &library.Series[i]
+		parsedHTML, err := convertMarkdownToHTML(series.Content.Raw)
+		if err != nil {
+			return fmt.Errorf("[WRITE LIBRARY] \"%s\": series \"%s\": %w", library.InputPath, series.Title, err)
+		}
+		series.Content.AddFormat("html", parsedHTML)
+	}
+
+	// --- Books ---
+	errs := make([]error, len(library.Books))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, book := range library.Books {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = RenderBookFS(book, book.InputPath, filepath.Join(outputDir, book.Slug()), layouts)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	// --- Library pages ---
+	parsedHTML, err := convertMarkdownToHTML(library.Content.Raw)
+	if err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+	library.Content.AddFormat("html", parsedHTML)
+
+	// static layout files (e.g. stylesheets) are shared by the library's pages
+	if err := copyDirectory(layouts, outputDir, layoutTemplates); err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	indexTpl, err := parseLibraryTemplate(layouts, "index.html", "index.html")
+	if err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	if err := executeTemplateToFile(indexTpl, library, filepath.Join(outputDir, "index.html")); err != nil {
+		return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+	}
+
+	for _, kind := range []struct {
+		tplName  string
+		dir      string
+		listings []pub.Listing
+	}{
+		{"tag.html", pub.LibraryTagsDirName, library.TagListings()},
+		{"author.html", pub.LibraryAuthorsDirName, library.AuthorListings()},
+		{"series.html", pub.LibrarySeriesDirName, library.SeriesListings()},
+	} {
+		if len(kind.listings) == 0 {
+			continue
+		}
+
+		defaultName := "listing.html"
+		if _, ok := defaultLibraryTemplates[kind.tplName]; ok {
+			defaultName = kind.tplName
+		}
+
+		tpl, err := parseLibraryTemplate(layouts, kind.tplName, defaultName)
+		if err != nil {
+			return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+		}
+
+		dir := filepath.Join(outputDir, kind.dir)
+		if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
+			return fmt.Errorf("[WRITE LIBRARY] \"%s\": %w", library.InputPath, err)
+		}
+
+		for _, listing := range kind.listings {
+			data := ListingData{Listing: listing, Library: library}
+			if err := executeTemplateToFile(tpl, data, filepath.Join(dir, listing.Slug+".html")); err != nil {
+				return fmt.Errorf("[WRITE LIBRARY] \"%s\": %s \"%s\": %w", library.InputPath, kind.dir, listing.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseLibraryTemplate parses the template name of "_library", or defaultName.
+func parseLibraryTemplate(layouts fs.FS, name, defaultName string) (*template.Template, error) {
+	tplPath := path.Join(LibraryLayoutsDirName, name)
+	tpl := template.New(name).Funcs(TplFuncs)
+	if _, err := fs.Stat(layouts, tplPath); err == nil {
+		return tpl.ParseFS(layouts, tplPath)
+	}
+
+	return tpl.Parse(defaultLibraryTemplates[defaultName])
+}
+
+func executeTemplateToFile(tpl *template.Template, data any, outputPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tpl.Execute(f, data)
+}
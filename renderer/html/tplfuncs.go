@@ -2,12 +2,27 @@ package html
 
 import (
 	"errors"
+	"fmt"
 	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/JessebotX/pub"
 )
 
 var (
 	ErrNotFloat = errors.New("arguments must be rational numbers")
 	ErrNotInt   = errors.New("arguments must allow conversion into an integer (float/decimal numbers are truncated i.e. 3.9 => 3)")
+	ErrNotDate  = errors.New("argument must be a date (pub.DateTime)")
+
+	ErrNotBookOrChapter    = errors.New("argument must be a book or a chapter (pub.Book or pub.Chapter)")
+	ErrWarningStyleUnknown = errors.New("content warning style must be one of the following: " + WarningStyleCollapsed + ", " + WarningStyleInterstitial)
+)
+
+// Styles of the content warning block written by the "contentWarnings" template function.
+const (
+	WarningStyleCollapsed    = "collapsed"
+	WarningStyleInterstitial = "interstitial"
 )
 
 var TplFuncs = template.FuncMap{
@@ -19,6 +34,10 @@ var TplFuncs = template.FuncMap{
 	"dec":   dec,
 	"float": convFloat,
 	"int":   convInt,
+	"date":  formatDate,
+
+	"contentWarnings": contentWarnings,
+	"advisoryMeta":    advisoryMeta,
 }
 
 func convInt(num any) (int, error) {
@@ -134,3 +153,135 @@ func dec(num any) (float64, error) {
 
 	return n - 1, nil
 }
+
+// formatDate formats a date in the language of languageCode, e.g.
+// {{ date .DatePublished .Book.LanguageCode }}.
+func formatDate(date any, languageCode string) (string, error) {
+	switch d := date.(type) {
+	case pub.DateTime:
+		return d.Localized(languageCode), nil
+	case *pub.DateTime:
+		if d == nil {
+			return "", nil
+		}
+		return d.Localized(languageCode), nil
+	}
+
+	return "", ErrNotDate
+}
+
+// advisories returns the content warnings, rating and language of v.
+func advisories(v any) ([]pub.AdvisoryTerm, *pub.AdvisoryTerm, string, error) {
+	switch x := v.(type) {
+	case pub.Book:
+		return x.ContentWarningTerms(), x.RatingTerm(), x.LanguageCode, nil
+	case *pub.Book:
+		return x.ContentWarningTerms(), x.RatingTerm(), x.LanguageCode, nil
+	case pub.Chapter:
+		return x.ContentWarningTerms(), x.RatingTerm(), chapterLanguageCode(&x), nil
+	case *pub.Chapter:
+		return x.ContentWarningTerms(), x.RatingTerm(), chapterLanguageCode(x), nil
+	}
+
+	return nil, nil, "", ErrNotBookOrChapter
+}
+
+func chapterLanguageCode(chapter *pub.Chapter) string {
+	if chapter.LanguageCode == "" && chapter.Book != nil {
+		return chapter.Book.LanguageCode
+	}
+
+	return chapter.LanguageCode
+}
+
+// warningLabels are the text of the content warning block in a language.
+type warningLabels struct {
+	Summary  string
+	Rated    string
+	Continue string
+}
+
+var contentWarningLabels = map[string]warningLabels{
+	"en": {Summary: "Content warnings", Rated: "Rated %s", Continue: "Continue reading"},
+	"es": {Summary: "Advertencias de contenido", Rated: "Clasificación: %s", Continue: "Seguir leyendo"},
+	"fr": {Summary: "Avertissements de contenu", Rated: "Classification : %s", Continue: "Continuer la lecture"},
+	"de": {Summary: "Inhaltswarnungen", Rated: "Altersfreigabe: %s", Continue: "Weiterlesen"},
+}
+
+// contentWarnings writes the content warnings and rating of a book or chapter as
+// a block in style, e.g. {{ contentWarnings . "collapsed" }}.
+//
+// The labels of the block are written in English, Spanish, French or German.
+func contentWarnings(v any, style string) (template.HTML, error) {
+	warnings, rating, languageCode, err := advisories(v)
+	if err != nil {
+		return "", err
+	}
+
+	if style != WarningStyleCollapsed && style != WarningStyleInterstitial {
+		return "", ErrWarningStyleUnknown
+	}
+
+	if len(warnings) == 0 && rating == nil {
+		return "", nil
+	}
+
+	language, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(languageCode, "_", "-")), "-")
+	labels, ok := contentWarningLabels[language]
+	if !ok {
+		labels = contentWarningLabels["en"]
+	}
+
+	var b strings.Builder
+	if style == WarningStyleCollapsed {
+		b.WriteString(`<details class="content-warnings"><summary>` + template.HTMLEscapeString(labels.Summary))
+		if rating != nil {
+			b.WriteString(" (" + template.HTMLEscapeString(rating.Name) + ")")
+		}
+		b.WriteString("</summary>")
+	} else {
+		b.WriteString(`<section class="content-warnings content-warnings-interstitial" role="alert">`)
+		if rating != nil {
+			b.WriteString(`<p class="content-rating">` + template.HTMLEscapeString(fmt.Sprintf(labels.Rated, rating.Name)))
+			if rating.MinimumAge > 0 {
+				b.WriteString(" (" + strconv.Itoa(rating.MinimumAge) + "+)")
+			}
+			b.WriteString("</p>")
+		}
+	}
+
+	if len(warnings) > 0 {
+		b.WriteString("<ul>")
+		for _, warning := range warnings {
+			b.WriteString("<li><strong>" + template.HTMLEscapeString(warning.Name) + "</strong>")
+			if warning.Description != "" {
+				b.WriteString(": " + template.HTMLEscapeString(warning.Description))
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</ul>")
+	}
+
+	if style == WarningStyleCollapsed {
+		b.WriteString("</details>")
+	} else {
+		b.WriteString(`<p><a href="#content">` + template.HTMLEscapeString(labels.Continue) + `</a></p></section>`)
+	}
+
+	return template.HTML(b.String()), nil
+}
+
+// advisoryMeta writes <meta name="rating" content="adult"> for adult ratings,
+// e.g. {{ advisoryMeta . }} in <head>.
+func advisoryMeta(v any) (template.HTML, error) {
+	_, rating, _, err := advisories(v)
+	if err != nil {
+		return "", err
+	}
+
+	if rating != nil && rating.MinimumAge >= 18 {
+		return `<meta name="rating" content="adult">`, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,112 @@
+package pub
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Role is a MARC relator code describing what a [Profile] contributed to a work.
+// See <https://www.loc.gov/marc/relators/relaterm.html>.
+type Role string
+
+const (
+	RoleAdapter             Role = "adp"
+	RoleAnnotator           Role = "ann"
+	RoleArtist              Role = "art"
+	RoleAuthor              Role = "aut"
+	RoleAuthorOfAfterword   Role = "aft"
+	RoleAuthorOfForeword    Role = "aui"
+	RoleBookDesigner        Role = "bkd"
+	RoleCartographer        Role = "ctg"
+	RoleColorist            Role = "clr"
+	RoleCompiler            Role = "com"
+	RoleContributor         Role = "ctb"
+	RoleCoverDesigner       Role = "cov"
+	RoleEditor              Role = "edt"
+	RoleIllustrator         Role = "ill"
+	RoleNarrator            Role = "nrt"
+	RolePhotographer        Role = "pht"
+	RoleProofreader         Role = "pfr"
+	RolePublisher           Role = "pbl"
+	RoleSponsor             Role = "spn"
+	RoleTranslator          Role = "trl"
+	RoleTranscriber         Role = "trc"
+	RoleCommentator         Role = "cmm"
+	RoleEditorOfCompilation Role = "edc"
+)
+
+var (
+	RoleMap = map[string]Role{
+		"adapter":               RoleAdapter,
+		"annotator":             RoleAnnotator,
+		"artist":                RoleArtist,
+		"author":                RoleAuthor,
+		"author of afterword":   RoleAuthorOfAfterword,
+		"author of foreword":    RoleAuthorOfForeword,
+		"book designer":         RoleBookDesigner,
+		"cartographer":          RoleCartographer,
+		"colorist":              RoleColorist,
+		"commentator":           RoleCommentator,
+		"compiler":              RoleCompiler,
+		"contributor":           RoleContributor,
+		"cover designer":        RoleCoverDesigner,
+		"editor":                RoleEditor,
+		"editor of compilation": RoleEditorOfCompilation,
+		"illustrator":           RoleIllustrator,
+		"narrator":              RoleNarrator,
+		"photographer":          RolePhotographer,
+		"proofreader":           RoleProofreader,
+		"publisher":             RolePublisher,
+		"sponsor":               RoleSponsor,
+		"transcriber":           RoleTranscriber,
+		"translator":            RoleTranslator,
+	}
+)
+
+type ErrRoleUnmarshalUnrecognized struct {
+	RoleString string
+}
+
+func (e ErrRoleUnmarshalUnrecognized) Error() string {
+	return fmt.Sprintf("role: unrecognized value \"%s\" (value must be a MARC relator code or one of the following (case doesn't matter): %v)", e.RoleString, strings.Join(slices.Sorted(maps.Keys(RoleMap)), ", "))
+}
+
+// UnmarshalText accepts either a MARC relator code (e.g. "trl") or its term (e.g. "translator").
+func (r *Role) UnmarshalText(text []byte) error {
+	vStr := strings.ToLower(strings.TrimSpace(string(text)))
+
+	// an empty role is unset (e.g. in an exported book model)
+	if vStr == "" {
+		*r = ""
+		return nil
+	}
+
+	if v, ok := RoleMap[vStr]; ok {
+		*r = v
+		return nil
+	}
+
+	if slices.Contains(slices.Collect(maps.Values(RoleMap)), Role(vStr)) {
+		*r = Role(vStr)
+		return nil
+	}
+
+	return ErrRoleUnmarshalUnrecognized{RoleString: string(text)}
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r), nil
+}
+
+// Name returns the MARC relator term of the role, or the role if it has none.
+func (r Role) Name() string {
+	for k, v := range RoleMap {
+		if v == r {
+			return k
+		}
+	}
+
+	return string(r)
+}
@@ -0,0 +1,305 @@
+package pub
+
+//go:generate go run ./internal/schemadocs -o schema_docs.go
+
+import (
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// JSONSchemaDraft is the version of JSON Schema of generated schemas.
+	JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+)
+
+// JSONSchema is a JSON Schema document, or one of its subschemas. Marshal it with [encoding/json].
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+// NewJSONSchema returns a JSON Schema document of the YAML accepted for v (e.g. a
+// [Book] or a []Chapter), from the json tags and doc comments of its type.
+func NewJSONSchema(v any) *JSONSchema {
+	g := jsonSchemaGenerator{definitions: make(map[string]*JSONSchema)}
+	schema := g.schemaOf(reflect.TypeOf(v))
+
+	// refer to the definition of the root type from the root of the document, and keep its description
+	if name := strings.TrimPrefix(schema.Ref, "#/definitions/"); schema.Ref != "" {
+		schema.Title = name
+		schema.Description = g.definitions[name].Description
+	}
+	schema.Schema = JSONSchemaDraft
+	if len(g.definitions) > 0 {
+		schema.Definitions = g.definitions
+	}
+
+	return schema
+}
+
+// BookJSONSchema returns the JSON Schema document of pub.yml.
+func BookJSONSchema() *JSONSchema {
+	schema := NewJSONSchema(bookConfig{})
+	schema.Title = BookConfigFileName
+	schema.Description = schemaDocs["Book"]
+
+	return schema
+}
+
+// ChaptersJSONSchema returns the JSON Schema document of nav.yml.
+func ChaptersJSONSchema() *JSONSchema {
+	g := jsonSchemaGenerator{definitions: make(map[string]*JSONSchema)}
+
+	return &JSONSchema{
+		Schema:      JSONSchemaDraft,
+		Title:       BookChaptersConfigFileName,
+		Description: schemaDocs["Chapter"],
+		AnyOf: []*JSONSchema{
+			g.schemaOf(reflect.TypeFor[[]Chapter]()),
+			g.schemaOf(reflect.TypeFor[navConfig]()),
+		},
+		Definitions: g.definitions,
+	}
+}
+
+// ReadingOrdersJSONSchema returns the JSON Schema document of reading_orders.yml.
+func ReadingOrdersJSONSchema() *JSONSchema {
+	schema := NewJSONSchema([]ReadingOrder{})
+	schema.Title = BookReadingOrdersConfigFileName
+	schema.Description = schemaDocs["ReadingOrder"]
+
+	return schema
+}
+
+type jsonSchemaGenerator struct {
+	definitions map[string]*JSONSchema
+}
+
+var (
+	contentType          = reflect.TypeFor[Content]()
+	dateTimeType         = reflect.TypeFor[DateTime]()
+	statusType           = reflect.TypeFor[Status]()
+	chapterStateType     = reflect.TypeFor[ChapterState]()
+	chapterKindType      = reflect.TypeFor[ChapterKind]()
+	numberingStyleType   = reflect.TypeFor[NumberingStyle]()
+	roleType             = reflect.TypeFor[Role]()
+	identifiersType      = reflect.TypeFor[Identifiers]()
+	profileType          = reflect.TypeFor[Profile]()
+	seriesVolumeType     = reflect.TypeFor[SeriesVolume]()
+	knownIdentifierNames = []string{IdentifierISBN10, IdentifierISBN13, IdentifierISSN, IdentifierDOI, IdentifierUUID, IdentifierASIN}
+)
+
+func (g *jsonSchemaGenerator) schemaOf(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// types decoded by custom unmarshalers
+	switch t {
+	case contentType:
+		return &JSONSchema{Type: "string", Description: schemaDocs[t.Name()]}
+	case dateTimeType:
+		return &JSONSchema{
+			Description: schemaDocs[t.Name()],
+			AnyOf: []*JSONSchema{
+				{Type: "string", Pattern: dateTimePattern()},
+				{Type: "integer", Description: "A year"},
+			},
+		}
+	case statusType:
+		return enumSchema(slices.Collect(maps.Keys(StatusMap)), schemaDocs[t.Name()])
+	case chapterStateType:
+		return enumSchema(slices.Collect(maps.Keys(ChapterStateMap)), schemaDocs[t.Name()])
+	case chapterKindType:
+		return enumSchema(slices.Collect(maps.Keys(ChapterKindMap)), schemaDocs[t.Name()])
+	case numberingStyleType:
+		return enumSchema(slices.Collect(maps.Keys(NumberingStyleMap)), schemaDocs[t.Name()])
+	case roleType:
+		values := slices.Collect(maps.Keys(RoleMap))
+		for _, code := range RoleMap {
+			values = append(values, string(code))
+		}
+		return enumSchema(values, schemaDocs[t.Name()])
+	case identifiersType:
+		schema := &JSONSchema{
+			Type:                 "object",
+			Description:          schemaDocs[t.Name()],
+			Properties:           make(map[string]*JSONSchema),
+			AdditionalProperties: &JSONSchema{Type: []string{"string", "number"}},
+		}
+		for _, name := range knownIdentifierNames {
+			schema.Properties[name] = &JSONSchema{Type: []string{"string", "number"}}
+		}
+		return schema
+	case profileType, seriesVolumeType:
+		// either an ID or the full value
+		return &JSONSchema{
+			Description: schemaDocs[t.Name()],
+			AnyOf:       []*JSONSchema{{Type: "string"}, g.structRef(t)},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return &JSONSchema{Type: "object"}
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" || !unicode.IsUpper([]rune(t.Name())[0]) {
+			return g.structSchema(t)
+		}
+		return g.structRef(t)
+	}
+
+	return &JSONSchema{}
+}
+
+// structRef returns a reference to the definition of the named struct type t.
+func (g *jsonSchemaGenerator) structRef(t reflect.Type) *JSONSchema {
+	name := t.Name()
+	if _, ok := g.definitions[name]; !ok {
+		// reserve the name first, so that recursive types refer to themselves
+		g.definitions[name] = &JSONSchema{}
+		*g.definitions[name] = *g.structSchema(t)
+	}
+
+	return &JSONSchema{Ref: "#/definitions/" + name}
+}
+
+func (g *jsonSchemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	schema := &JSONSchema{
+		Type:                 "object",
+		Description:          schemaDocs[t.Name()],
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: false,
+	}
+	g.addProperties(schema, t)
+
+	return schema
+}
+
+// addProperties adds a property to schema for each YAML field of struct type t.
+func (g *jsonSchemaGenerator) addProperties(schema *JSONSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || !field.Anonymous {
+			continue
+		}
+
+		embedded := &JSONSchema{Properties: make(map[string]*JSONSchema)}
+		g.addProperties(embedded, field.Type)
+		for name, property := range embedded.Properties {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Properties[name] = property
+			}
+		}
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, ok := yamlFieldName(field)
+		if !ok {
+			continue
+		}
+
+		property := g.schemaOf(field.Type)
+		if description := schemaDocs[t.Name()+"."+field.Name]; description != "" {
+			property.Description = description
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// enumSchema returns the schema of a string that is one of values, in any case.
+func enumSchema(values []string, description string) *JSONSchema {
+	slices.Sort(values)
+	values = slices.Compact(values)
+
+	alternatives := make([]string, len(values))
+	for i, value := range values {
+		var b strings.Builder
+		for _, r := range value {
+			if unicode.IsLetter(r) && unicode.ToLower(r) != unicode.ToUpper(r) {
+				b.WriteString("[" + string(unicode.ToLower(r)) + string(unicode.ToUpper(r)) + "]")
+				continue
+			}
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+		alternatives[i] = b.String()
+	}
+
+	return &JSONSchema{
+		Description: description,
+		AnyOf: []*JSONSchema{
+			{Type: "string", Enum: values},
+			{Type: "string", Pattern: "^(?:" + strings.Join(alternatives, "|") + ")$"},
+		},
+	}
+}
+
+// dateTimePattern returns a regular expression matching [DateTimeLayouts].
+func dateTimePattern() string {
+	elements := []struct {
+		layout  string
+		pattern string
+	}{
+		{"Z07:00", `(?:Z|[+-]\d{2}:\d{2})`},
+		{"2006", `\d{4}`},
+		{"01", `\d{2}`},
+		{"02", `\d{2}`},
+		{"15", `\d{2}`},
+		{"04", `\d{2}`},
+		{"05", `\d{2}(?:[.,]\d+)?`}, // fractional seconds are always accepted when parsing
+	}
+
+	alternatives := make([]string, len(DateTimeLayouts))
+	for i, layout := range DateTimeLayouts {
+		var b strings.Builder
+	scan:
+		for layout != "" {
+			for _, element := range elements {
+				if rest, ok := strings.CutPrefix(layout, element.layout); ok {
+					b.WriteString(element.pattern)
+					layout = rest
+					continue scan
+				}
+			}
+
+			b.WriteString(regexp.QuoteMeta(layout[:1]))
+			layout = layout[1:]
+		}
+		alternatives[i] = b.String()
+	}
+
+	return "^(?:" + strings.Join(alternatives, "|") + ")$"
+}
@@ -0,0 +1,53 @@
+// Code generated by internal/schemadocs; DO NOT EDIT.
+
+package pub
+
+// schemaDocs holds the doc comment of each type (e.g. "Book") and struct field (e.g. "Book.Title") of the package.
+var schemaDocs = map[string]string{
+	"Advisories":                       "Advisories declares the content warnings and ratings a Book may use.\nRatings are listed from the least to the most restrictive.",
+	"AdvisoryTerm":                     "AdvisoryTerm is a content warning (e.g. \"violence\") or a rating (e.g. \"teen\").",
+	"AdvisoryTerm.MinimumAge":          "MinimumAge is the age that readers should be to read content with the rating (for ratings only).",
+	"Asset":                            "Asset represents a media element such as an image or video. Supports specifying multiple AssetDescriptors which will be used as fallback formats (in the specified order) when the asset is not supported by the application.\n\nFiles sharing a base name and media type (e.g. \"cover.avif\" and \"cover.jpg\")\nare one asset (e.g. \"cover\"), described by a sidecar (e.g. \"cover.jpg.yml\").",
+	"AssetDescriptor":                  "AssetDescriptor represents an individual file format of a media element.\nFormat is its MIME type (e.g. \"image/webp\"), and Type the top-level one.",
+	"Book":                             "Book represents a written work, which generally has an ordered list of 1 or more Chapters.",
+	"Book.Languages":                   "Languages are the editions of the book in each of its languages.",
+	"Book.ParentBook":                  "ParentBook is the book that a volume was split from, or nil.",
+	"Book.ReadingOrders":               "ReadingOrders are defined in nav.yml and reading_orders.yml, not pub.yml.",
+	"Book.Translations":                "Translations are the editions of the book in other languages, from translations/.",
+	"Book.Withheld":                    "Withheld holds the unique IDs of the draft and scheduled chapters left out.",
+	"BookOption":                       "BookOption configures how a Book is loaded by NewBookFS.",
+	"Chapter":                          "Chapter represents a division in a Book that contains its primary Content.\n\nFields may be defined in nav.yml and in the front matter of the content file,\nwhich overrides nav.yml except for Extra, whose keys are merged.\n\nAuthorsNotePrefix and AuthorsNoteSuffix may also be written in sidecar files\n(e.g. \"chapter-1.note-before.md\") or in sections of the content file.\n\nA nav.yml entry with a Glob (e.g. \"part-2/*.md\") becomes a chapter per matching\nfile. Without a nav.yml, chapters are discovered from the chapters directory.",
+	"Chapter.Untranslated":             "Untranslated is set on the chapters of a translation with the original's content.",
+	"ChapterKind":                      "ChapterKind is the structural role of a Chapter in its book.",
+	"ChapterState":                     "ChapterState is the publication state of a Chapter. The zero value is ChapterPublished.",
+	"Content":                          "Content represents a body of text that is/can be parsed into different formats (e.g. Markdown to HTML, etc.).\n\nLazily loaded content keeps Raw empty until it is read with Content.Bytes.",
+	"DateTime":                         "DateTime is a point in time that is formatted as precisely as it was written.\nWithout a time zone, it is floating until the book's TimeZone is applied.",
+	"DateTimePrecision":                "DateTimePrecision is the smallest unit of time a DateTime was written with.",
+	"Diagnostic":                       "Diagnostic is an error or warning found while validating a Book, and where.\nLine and Column are 0 when the position is unknown.",
+	"Diagnostics":                      "Diagnostics is every Diagnostic found while validating a Book, as an error.",
+	"ErrSeriesMissingNumber.Ambiguous": "Ambiguous is set when some books are numbered by their position.",
+	"ErrSeriesMissingNumber.Last":      "Last is the last of a range of missing numbers starting at Number.",
+	"Identifiers":                      "Identifiers maps schemes (e.g. \"isbn-13\") to the identifiers of a Book or Chapter.",
+	"JSONSchema":                       "JSONSchema is a JSON Schema document, or one of its subschemas. Marshal it with encoding/json.",
+	"Language":                         "Language is an edition of a Book in one language: the original or a translation.",
+	"Language.Dir":                     "Dir is the path of the edition's directory relative to the current one (e.g.\n\"es/\" or \"../\").",
+	"Language.Translated":              "Translated is the number of translated chapters, out of Total. Missing holds\nthe unique IDs of the others.",
+	"Library":                          "Library is a collection of Books that are published together, described by\na library.yml. BookPaths may contain glob patterns (e.g. \"books/*\").\n\nThe series of the library's books are defined in series.yml, or SeriesFile.",
+	"License":                          "License is a license that applies to a Book or Chapter. Its text is set\ninline, read from FileName, or filled in from a known SPDX identifier.",
+	"Listing":                          "Listing is a named group of books of a Library (e.g. every book with a tag).",
+	"Listing.Series":                   "Series is the definition of the series listed, if any.",
+	"Numbering":                        "Numbering describes how the numbers of Chapters are formatted. Styles[i]\napplies at depth i, and the last style to any deeper levels.\n\nAppendices are numbered in the Appendices style, LettersUpper by default.",
+	"Profile":                          "Profile may represent an individual or an organization that is credited as either an author, contributor or publisher affliated with a Book.\n\nA profile may reference a profiles.yml entry by ID, as a plain string (e.g.\n\"jane-doe\") or as a mapping whose other fields override the entry.",
+	"ReadingOrder":                     "ReadingOrder is a named order to read the chapters of a Book in, besides the\norder of nav.yml (e.g. a chronological order).\n\nReading orders are defined under \"reading_orders\" of nav.yml, or in\nreading_orders.yml.",
+	"ReadingOrder.Chapters":            "Chapters are the readable chapters of the book listed by ChapterIDs.",
+	"Reference":                        "Reference represents an external link/address that is generally clickable.",
+	"Role":                             "Role is a MARC relator code describing what a Profile contributed to a work.\nSee <https://www.loc.gov/marc/relators/relaterm.html>.",
+	"Series":                           "Series describes a Book's relation to a set of other Book objects (i.e. prequels, sequels, side stories, sharing the same world/universe, etc.)\n\nIn a Library that defines the series, it is referenced by ID or Title, and\nNumber, Definition, Previous and Next are resolved from the definition.",
+	"SeriesDefinition":                 "SeriesDefinition is a series of a Library, defined in its series.yml.\nVolumes lists the books of the series in reading order.",
+	"SeriesDefinition.Slug":            "Slug is safe to use in file names and URLs (e.g. for the series' landing page).",
+	"SeriesVolume":                     "SeriesVolume is a book of a SeriesDefinition, as its unique ID or a mapping\nof \"book\" and \"number\". Without a number, it follows the previous volume.",
+	"Taxonomy":                         "Taxonomy is a way of classifying the Chapters of a Book (e.g. \"pov\"), whose\nterms chapters list under \"taxonomies\" (e.g. \"pov: Mira\").",
+	"Taxonomy.Terms":                   "Terms are the terms used by the chapters of the book, sorted by name.",
+	"TaxonomyTerm":                     "TaxonomyTerm is a term of a Taxonomy (e.g. \"Mira\"), and the chapters using it.",
+	"TaxonomyTerm.TaxonomySlug":        "TaxonomySlug is the Slug of the term's taxonomy.",
+}
@@ -0,0 +1,104 @@
+package pub
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestDateTimePattern(t *testing.T) {
+	pattern := regexp.MustCompile(dateTimePattern())
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"2025", true},
+		{"2025-09", true},
+		{"2025-09-08", true},
+		{"2025-09-08 14:30", true},
+		{"2025-09-08T14:30:15.5", true},
+		{"2025-09-08T14:30:15Z", true},
+		{"2025-09-08 -04:00", true},
+		{"2025 Z", true},
+		{"25-09-08", false},
+		{"2025-9-8", false},
+		{"September 2025", false},
+	}
+
+	for _, tt := range tests {
+		if got := pattern.MatchString(tt.input); got != tt.want {
+			t.Errorf("dateTimePattern() matches %q = %t, want %t", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEnumSchema(t *testing.T) {
+	schema := enumSchema([]string{"ongoing", "completed", "ongoing"}, "")
+	if !slices.Equal(schema.AnyOf[0].Enum, []string{"completed", "ongoing"}) {
+		t.Errorf("Enum = %v, want [completed ongoing]", schema.AnyOf[0].Enum)
+	}
+
+	pattern := regexp.MustCompile(schema.AnyOf[1].Pattern)
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"ongoing", true},
+		{"Completed", true},
+		{"ONGOING", true},
+		{"hiatus", false},
+		{"ongoing2", false},
+	}
+
+	for _, tt := range tests {
+		if got := pattern.MatchString(tt.input); got != tt.want {
+			t.Errorf("pattern matches %q = %t, want %t", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBookJSONSchema(t *testing.T) {
+	schema := BookJSONSchema()
+
+	tests := []struct {
+		definition string
+		property   string
+	}{
+		{"Book", "unique_id"},
+		{"Book", "date_published_start"},
+		{"Chapter", "content_file_name"},
+		{"Chapter", "state"},
+		{"Profile", "name"},
+		{"Series", "title"},
+		{"Reference", "address"},
+		{"Copyright", "licenses"},
+	}
+
+	for _, tt := range tests {
+		definition := schema.Definitions[tt.definition]
+		if definition == nil {
+			t.Errorf("BookJSONSchema() has no %s definition", tt.definition)
+			continue
+		}
+		if definition.Properties[tt.property] == nil {
+			t.Errorf("%s has no property %q", tt.definition, tt.property)
+		}
+	}
+
+	book := schema.Definitions["Book"]
+	if book.Description == "" {
+		t.Errorf("Book has no description")
+	}
+	if book.Properties["Content"] != nil {
+		t.Errorf("Book has a property for Content, which has no json tag")
+	}
+	if book.Properties["translations"] != nil {
+		t.Errorf("Book has a property for Translations, which are loaded from translations/")
+	}
+
+	status := book.Properties["status"]
+	if status == nil || len(status.AnyOf) == 0 || len(status.AnyOf[0].Enum) != len(StatusMap) {
+		t.Errorf("Book.status = %+v, want an enum of every StatusMap key", status)
+	}
+}
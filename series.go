@@ -1,34 +1,330 @@
 package pub
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	SeriesConfigFileName = "series.yml"
 )
 
 var (
-	ErrSeriesMissingTitle = errors.New("series: missing title")
+	ErrSeriesMissingTitle        = errors.New("series: missing title (or the id of a series defined in " + SeriesConfigFileName + ")")
+	ErrSeriesDefinitionMissingID = errors.New("series: missing id")
+	ErrSeriesDefinitionNoBooks   = errors.New("series: no books (list the unique IDs of the series' books under \"books\", in reading order)")
+	ErrSeriesVolumeMissingBook   = errors.New("series: missing the unique ID of the book")
 )
 
+type ErrSeriesUnknown struct {
+	Name string
+}
+
+func (e ErrSeriesUnknown) Error() string {
+	return fmt.Sprintf("series \"%s\" is not defined in %s", e.Name, SeriesConfigFileName)
+}
+
+type ErrSeriesOutsideLibrary struct {
+	ID string
+}
+
+func (e ErrSeriesOutsideLibrary) Error() string {
+	return fmt.Sprintf("series \"%s\" cannot be resolved, as series are defined in the %s of a library and the book is not built as part of one (build the library, or give the series a title instead of an id)", e.ID, SeriesConfigFileName)
+}
+
+type ErrSeriesDuplicateID struct {
+	ID string
+}
+
+func (e ErrSeriesDuplicateID) Error() string {
+	return fmt.Sprintf("series: id \"%s\" is used by more than one series", e.ID)
+}
+
+type ErrSeriesBookNotFound struct {
+	Series   string
+	UniqueID string
+}
+
+func (e ErrSeriesBookNotFound) Error() string {
+	return fmt.Sprintf("series \"%s\": no book in the library has the unique ID \"%s\"", e.Series, e.UniqueID)
+}
+
+type ErrSeriesBookNotListed struct {
+	Series string
+}
+
+func (e ErrSeriesBookNotListed) Error() string {
+	return fmt.Sprintf("series \"%s\": book is not listed under the series' books in %s", e.Series, SeriesConfigFileName)
+}
+
+type ErrSeriesDuplicateBook struct {
+	Series   string
+	UniqueID string
+}
+
+func (e ErrSeriesDuplicateBook) Error() string {
+	return fmt.Sprintf("series \"%s\": book \"%s\" is listed more than once", e.Series, e.UniqueID)
+}
+
+type ErrSeriesDuplicateNumber struct {
+	Series string
+	Number float64
+}
+
+func (e ErrSeriesDuplicateNumber) Error() string {
+	return fmt.Sprintf("series \"%s\": number %s is used by more than one book", e.Series, formatSeriesNumber(e.Number))
+}
+
+type ErrSeriesMissingNumber struct {
+	Series string
+	Number float64
+
+	// Last is the last of a range of missing numbers starting at Number.
+	Last float64
+
+	// Ambiguous is set when some books are numbered by their position.
+	Ambiguous bool
+}
+
+func (e ErrSeriesMissingNumber) Error() string {
+	missing := "number " + formatSeriesNumber(e.Number)
+	if e.Last > e.Number {
+		missing = fmt.Sprintf("numbers %s to %s", formatSeriesNumber(e.Number), formatSeriesNumber(e.Last))
+	}
+
+	if e.Ambiguous {
+		return fmt.Sprintf("series \"%s\": no book has %s, while some books are numbered by their position in the list (give every book of the series a number)", e.Series, missing)
+	}
+
+	return fmt.Sprintf("series \"%s\": no book has %s", e.Series, missing)
+}
+
+type ErrSeriesNumberMismatch struct {
+	Series   string
+	Number   float64
+	Expected float64
+}
+
+func (e ErrSeriesNumberMismatch) Error() string {
+	return fmt.Sprintf("series \"%s\": number %s does not match the number in %s (%s)", e.Series, formatSeriesNumber(e.Number), SeriesConfigFileName, formatSeriesNumber(e.Expected))
+}
+
 // Series describes a [Book]'s relation to a set of other [Book] objects (i.e. prequels, sequels, side stories, sharing the same world/universe, etc.)
+//
+// In a [Library] that defines the series, it is referenced by ID or Title, and
+// Number, Definition, Previous and Next are resolved from the definition.
 type Series struct {
+	ID              string      `json:"id"`
 	Title           string      `json:"title"`
 	TitlesAlternate []string    `json:"titles_alternate"`
 	Number          float64     `json:"number"`
 	Description     string      `json:"description"`
 	Content         Content     `json:"content"`
 	External        []Reference `json:"external"`
+
+	Definition *SeriesDefinition `json:"-"`
+	Previous   *Book             `json:"-"`
+	Next       *Book             `json:"-"`
 }
 
 func (s Series) EnsureValid() error {
+	return validateWith(func(v *validator) {
+		s.validate(v, nil)
+	})
+}
+
+func (s *Series) validate(v *validator, loc location) {
+	if s.Title == "" && s.ID == "" {
+		v.error(loc.field("title"), ErrSeriesMissingTitle)
+	}
+
+	for i := range s.External {
+		e := &s.External[i]
+		e.validate(v, loc.index("external", i), fmt.Sprintf("series \"%s\": ", s.Title))
+	}
+}
+
+// ReadingOrder returns every volume of the series in reading order, or nil.
+func (s Series) ReadingOrder() []SeriesVolume {
+	if s.Definition == nil {
+		return nil
+	}
+
+	return s.Definition.Volumes
+}
+
+// SeriesDefinition is a series of a [Library], defined in its series.yml.
+// Volumes lists the books of the series in reading order.
+type SeriesDefinition struct {
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	TitlesAlternate []string       `json:"titles_alternate"`
+	Description     string         `json:"description"`
+	Content         Content        `json:"content"`
+	External        []Reference    `json:"external"`
+	Volumes         []SeriesVolume `json:"books"`
+	Extra           map[string]any `json:"extra"`
+
+	// Slug is safe to use in file names and URLs (e.g. for the series' landing page).
+	Slug string `json:"-"`
+}
+
+// SeriesVolume is a book of a [SeriesDefinition], as its unique ID or a mapping
+// of "book" and "number". Without a number, it follows the previous volume.
+type SeriesVolume struct {
+	UniqueID string  `json:"book"`
+	Number   float64 `json:"number"`
+
+	Book *Book `json:"-"`
+
+	numbered bool
+}
+
+func (s *SeriesVolume) UnmarshalYAML(unmarshal func(any) error) error {
+	var uniqueID string
+	if err := unmarshal(&uniqueID); err == nil {
+		*s = SeriesVolume{UniqueID: uniqueID}
+		return nil
+	}
+
+	var volume struct {
+		UniqueID string   `json:"book"`
+		Number   *float64 `json:"number"`
+	}
+	if err := unmarshal(&volume); err != nil {
+		return err
+	}
+
+	*s = SeriesVolume{UniqueID: volume.UniqueID}
+	if volume.Number != nil {
+		s.Number, s.numbered = *volume.Number, true
+	}
+
+	return nil
+}
+
+// NumberString returns the number of the volume without trailing zeros (e.g. "2" or "2.5").
+func (s SeriesVolume) NumberString() string {
+	return formatSeriesNumber(s.Number)
+}
+
+func (s *SeriesDefinition) validate(v *validator, loc location) {
+	if s.ID == "" {
+		v.error(loc.field("id"), ErrSeriesDefinitionMissingID)
+	}
+
 	if s.Title == "" {
-		return ErrSeriesMissingTitle
+		s.Title = s.ID
 	}
 
-	for _, e := range s.External {
-		if err := e.EnsureValid(); err != nil {
-			return fmt.Errorf("series \"%s\": %w", s.Title, err)
+	if len(s.Volumes) == 0 {
+		v.error(loc.field("books"), ErrSeriesDefinitionNoBooks)
+	}
+
+	for i := range s.External {
+		e := &s.External[i]
+		e.validate(v, loc.index("external", i), fmt.Sprintf("series \"%s\": ", s.Title))
+	}
+
+	// number volumes without a number after the previous volume
+	var next float64 = 1
+	numbers := make(map[float64]bool)
+	uniqueIDs := make(map[string]bool)
+	for i := range s.Volumes {
+		volume := &s.Volumes[i]
+		volume.UniqueID = strings.TrimSpace(volume.UniqueID)
+		if volume.UniqueID == "" {
+			v.error(loc.index("books", i), ErrSeriesVolumeMissingBook)
+		}
+		if !volume.numbered {
+			volume.Number = next
+		}
+		next = math.Floor(volume.Number) + 1
+
+		if uniqueIDs[volume.UniqueID] {
+			v.error(loc.index("books", i), ErrSeriesDuplicateBook{Series: s.Title, UniqueID: volume.UniqueID})
 		}
+		uniqueIDs[volume.UniqueID] = true
+
+		if numbers[volume.Number] {
+			v.error(loc.index("books", i), ErrSeriesDuplicateNumber{Series: s.Title, Number: volume.Number})
+		}
+		numbers[volume.Number] = true
 	}
 
-	return nil
+	// whole numbers between the first and last volume should all be used, and a
+	// gap is only certain when every volume is numbered explicitly
+	if len(s.Volumes) > 0 {
+		first, last := math.Inf(1), math.Inf(-1)
+		ambiguous := false
+		for _, volume := range s.Volumes {
+			first, last = min(first, volume.Number), max(last, volume.Number)
+			ambiguous = ambiguous || !volume.numbered
+		}
+
+		// each run of missing numbers is reported once
+		for n := math.Ceil(first); n <= last; n++ {
+			if numbers[n] {
+				continue
+			}
+
+			err := ErrSeriesMissingNumber{Series: s.Title, Number: n, Last: n, Ambiguous: ambiguous}
+			for !numbers[err.Last+1] && err.Last+1 <= last {
+				err.Last++
+			}
+			n = err.Last
+
+			if ambiguous {
+				v.error(loc.field("books"), err)
+			} else {
+				v.warning(loc.field("books"), err)
+			}
+		}
+	}
+}
+
+// volumeIndex returns the index of the book with the given unique ID, or -1.
+func (s SeriesDefinition) volumeIndex(uniqueID string) int {
+	for i, volume := range s.Volumes {
+		if volume.UniqueID == uniqueID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func formatSeriesNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// resolve returns the reference s completed by the definition for volume i.
+func (s *SeriesDefinition) resolve(series Series, i int) Series {
+	series.ID = s.ID
+	series.Title = cmp.Or(series.Title, s.Title)
+	series.Description = cmp.Or(series.Description, s.Description)
+	series.Number = s.Volumes[i].Number
+	series.Definition = s
+
+	if len(series.TitlesAlternate) == 0 {
+		series.TitlesAlternate = s.TitlesAlternate
+	}
+
+	if len(series.External) == 0 {
+		series.External = s.External
+	}
+
+	for j := i - 1; j >= 0 && series.Previous == nil; j-- {
+		series.Previous = s.Volumes[j].Book
+	}
+
+	for j := i + 1; j < len(s.Volumes) && series.Next == nil; j++ {
+		series.Next = s.Volumes[j].Book
+	}
+
+	return series
 }
@@ -0,0 +1,71 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestSeriesDefinitionGaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		books    string
+		severity Severity
+		want     []ErrSeriesMissingNumber
+	}{
+		{"no gap", "[a, b, c]", SeverityWarning, nil},
+		{"single gap", "[{book: a, number: 1}, {book: b, number: 3}]", SeverityWarning, []ErrSeriesMissingNumber{{Number: 2, Last: 2}}},
+		{"range reported once", "[{book: a, number: 1}, {book: b, number: 2}, {book: c, number: 6}]", SeverityWarning, []ErrSeriesMissingNumber{{Number: 3, Last: 5}}},
+		{"two ranges", "[{book: a, number: 1}, {book: b, number: 4}, {book: c, number: 7}]", SeverityWarning, []ErrSeriesMissingNumber{{Number: 2, Last: 3}, {Number: 5, Last: 6}}},
+		{"fractional numbers skipped", "[{book: a, number: 1}, {book: b, number: 1.5}, {book: c, number: 2}]", SeverityWarning, nil},
+		{"ambiguous", "[a, {book: b, number: 4}]", SeverityError, []ErrSeriesMissingNumber{{Number: 2, Last: 3, Ambiguous: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var series SeriesDefinition
+			if err := yaml.Unmarshal([]byte("id: s\ntitle: S\nbooks: "+tt.books+"\n"), &series); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+
+			v := validator{}
+			series.validate(&v, location{})
+
+			var got []ErrSeriesMissingNumber
+			for _, diagnostic := range v.diagnostics {
+				var missing ErrSeriesMissingNumber
+				if !errors.As(diagnostic, &missing) {
+					t.Errorf("validate() %v, want only ErrSeriesMissingNumber", diagnostic)
+					continue
+				}
+				if diagnostic.Severity != tt.severity {
+					t.Errorf("validate() severity = %v, want %v", diagnostic.Severity, tt.severity)
+				}
+				missing.Series = ""
+				got = append(got, missing)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validate() missing numbers = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrSeriesMissingNumber(t *testing.T) {
+	tests := []struct {
+		err  ErrSeriesMissingNumber
+		want string
+	}{
+		{ErrSeriesMissingNumber{Series: "S", Number: 2, Last: 2}, "series \"S\": no book has number 2"},
+		{ErrSeriesMissingNumber{Series: "S", Number: 3, Last: 5}, "series \"S\": no book has numbers 3 to 5"},
+		{ErrSeriesMissingNumber{Series: "S", Number: 2, Last: 3, Ambiguous: true}, "series \"S\": no book has numbers 2 to 3, while some books are numbered by their position in the list (give every book of the series a number)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
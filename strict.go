@@ -0,0 +1,193 @@
+package pub
+
+import (
+	"encoding"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+type ErrUnknownKey struct {
+	Key        string
+	Suggestion string
+}
+
+func (e ErrUnknownKey) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("unknown key \"%s\" (arbitrary data belongs under \"extra\")", e.Key)
+	}
+
+	return fmt.Sprintf("unknown key \"%s\" (did you mean \"%s\"?)", e.Key, e.Suggestion)
+}
+
+var (
+	textUnmarshalerType  = reflect.TypeFor[encoding.TextUnmarshaler]()
+	bytesUnmarshalerType = reflect.TypeFor[yaml.BytesUnmarshaler]()
+)
+
+// checkUnknownKeys reports the keys of each YAML source that match no field of
+// its schema, as warnings or, with [WithStrict], errors.
+func (v *validator) checkUnknownKeys(strict bool) {
+	severity := SeverityWarning
+	if strict {
+		severity = SeverityError
+	}
+
+	for _, filePath := range slices.Sorted(maps.Keys(v.sources)) {
+		source := v.sources[filePath]
+		if source.schema == nil {
+			continue
+		}
+
+		f, err := source.parsed()
+		if err != nil {
+			continue
+		}
+
+		for _, doc := range f.Docs {
+			walkUnknownKeys(doc.Body, source.schema, func(key ast.MapKeyNode, err error) {
+				token := key.GetToken()
+				v.addAt(severity, filePath, token.Position.Line+source.lineOffset, token.Position.Column, err)
+			})
+		}
+	}
+}
+
+// walkUnknownKeys calls report for each key in node that matches no field of t.
+func walkUnknownKeys(node ast.Node, t reflect.Type, report func(key ast.MapKeyNode, err error)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// values decoded by custom unmarshalers (e.g. Content, DateTime) have no keys
+	pt := reflect.PointerTo(t)
+	if pt.Implements(textUnmarshalerType) || pt.Implements(bytesUnmarshalerType) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *ast.DocumentNode:
+		walkUnknownKeys(n.Body, t, report)
+	case *ast.AnchorNode:
+		walkUnknownKeys(n.Value, t, report)
+	case *ast.TagNode:
+		walkUnknownKeys(n.Value, t, report)
+	case *ast.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for _, value := range n.Values {
+			walkUnknownKeys(value, t.Elem(), report)
+		}
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			walkUnknownKeys(value, t, report)
+		}
+	case *ast.MappingValueNode:
+		switch t.Kind() {
+		case reflect.Map:
+			walkUnknownKeys(n.Value, t.Elem(), report)
+		case reflect.Struct:
+			key := n.Key.GetToken().Value
+			if key == "<<" {
+				walkUnknownKeys(n.Value, t, report)
+				return
+			}
+
+			fields := yamlFieldTypes(t)
+			field, ok := fields[key]
+			if !ok {
+				report(n.Key, ErrUnknownKey{Key: key, Suggestion: closestString(key, slices.Sorted(maps.Keys(fields)))})
+				return
+			}
+
+			walkUnknownKeys(n.Value, field, report)
+		}
+	}
+}
+
+// yamlFieldTypes returns the types of the fields of struct t by YAML name.
+func yamlFieldTypes(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, ok := yamlFieldName(field)
+		if !ok {
+			continue
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		tag = field.Tag.Get("json")
+	}
+
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+
+	return name, true
+}
+
+// closestString returns the candidate s is likely a typo of, or "" if none.
+func closestString(s string, candidates []string) string {
+	var closest string
+	best := max(2, len(s)/3) + 1
+	for _, candidate := range candidates {
+		if d := editDistance(s, candidate); d < best {
+			closest, best = candidate, d
+		}
+	}
+
+	return closest
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment) distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	prev2 := make([]int, len(br)+1)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(br)]
+}
@@ -0,0 +1,106 @@
+package pub
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"title", "title", 0},
+		{"", "title", 5},
+		{"titel", "title", 1},
+		{"langauge_code", "language_code", 1},
+		{"date_publised", "date_published", 1},
+		{"tilte", "title", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestClosestString(t *testing.T) {
+	candidates := []string{"date_published", "description", "language_code", "title"}
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"langauge_code", "language_code"},
+		{"date_publised", "date_published"},
+		{"titel", "title"},
+		{"descripton", "description"},
+		{"colour", ""},
+	}
+
+	for _, tt := range tests {
+		if got := closestString(tt.s, candidates); got != tt.want {
+			t.Errorf("closestString(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		pubYML string
+		navYML string
+		want   []ErrUnknownKey
+	}{
+		{"none", "unique_id: a\ntitle: A\nlanguage_code: en\n", "", nil},
+		{"typo", "unique_id: a\ntitle: A\nlanguage_code: en\ndate_published_strat: 2024-01-01\n", "", []ErrUnknownKey{
+			{Key: "date_published_strat", Suggestion: "date_published_start"},
+		}},
+		{"nested", "unique_id: a\ntitle: A\nlanguage_code: en\nauthors:\n  - name: A\n    rloe: trl\n", "", []ErrUnknownKey{
+			{Key: "rloe", Suggestion: "role"},
+		}},
+		{"nav.yml", "unique_id: a\ntitle: A\nlanguage_code: en\n", "- content_file_name: one.md\n  titel: One\n", []ErrUnknownKey{
+			{Key: "titel", Suggestion: "title"},
+		}},
+		{"extra", "unique_id: a\ntitle: A\nlanguage_code: en\nextra:\n  anything: 1\n  nested:\n    goes: here\n", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"book/pub.yml":         {Data: []byte("schema: 1\n" + tt.pubYML)},
+				"book/chapters/one.md": {Data: []byte("One\n")},
+			}
+			if tt.navYML != "" {
+				fsys["book/nav.yml"] = &fstest.MapFile{Data: []byte(tt.navYML)}
+			}
+
+			book, err := NewBookFS(fsys, "book")
+			if err != nil {
+				t.Fatalf("NewBookFS() error = %v", err)
+			}
+
+			warnings := book.Diagnostics().Warnings()
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("warnings = %v, want %v", warnings, tt.want)
+			}
+			for i, want := range tt.want {
+				var got ErrUnknownKey
+				if !errors.As(warnings[i], &got) || got != want {
+					t.Errorf("warnings[%d] = %v, want %v", i, warnings[i], want)
+				}
+			}
+
+			_, err = NewBookFS(fsys, "book", WithStrict())
+			if got := err != nil; got != (len(tt.want) > 0) {
+				t.Errorf("NewBookFS(WithStrict()) error = %v, want error %t", err, len(tt.want) > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package pub
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrTaxonomyMissingID = errors.New("taxonomy: missing id")
+)
+
+type ErrTaxonomyDuplicateID struct {
+	ID string
+}
+
+func (e ErrTaxonomyDuplicateID) Error() string {
+	return fmt.Sprintf("taxonomy: id \"%s\" is used by more than one taxonomy", e.ID)
+}
+
+type ErrTaxonomySlugUnsafe struct {
+	ID string
+}
+
+func (e ErrTaxonomySlugUnsafe) Error() string {
+	return fmt.Sprintf("taxonomy: id \"%s\" cannot name the taxonomy's directory (it must contain a letter or digit and must not start with \".\")", e.ID)
+}
+
+type ErrTaxonomyDuplicateSlug struct {
+	ID    string
+	Other string
+	Slug  string
+}
+
+func (e ErrTaxonomyDuplicateSlug) Error() string {
+	return fmt.Sprintf("taxonomy: ids \"%s\" and \"%s\" would both be written to the directory \"%s\" (give them ids that differ in more than punctuation)", e.Other, e.ID, e.Slug)
+}
+
+type ErrTaxonomyUnknown struct {
+	ID string
+}
+
+func (e ErrTaxonomyUnknown) Error() string {
+	return fmt.Sprintf("taxonomy \"%s\" is not declared under taxonomies in %s", e.ID, BookConfigFileName)
+}
+
+type ErrTaxonomyEmptyTerm struct {
+	Taxonomy string
+	Input    string
+}
+
+func (e ErrTaxonomyEmptyTerm) Error() string {
+	return fmt.Sprintf("taxonomy \"%s\": term \"%s\" must contain at least 1 letter or digit and must not start with \".\"", e.Taxonomy, e.Input)
+}
+
+// Taxonomy is a way of classifying the [Chapter]s of a [Book] (e.g. "pov"), whose
+// terms chapters list under "taxonomies" (e.g. "pov: [Mira]").
+type Taxonomy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Terms are the terms used by the chapters of the book, sorted by name.
+	Terms []TaxonomyTerm `json:"-"`
+	Slug  string         `json:"-"`
+
+	loc location
+}
+
+// TaxonomyTerm is a term of a [Taxonomy] (e.g. "Mira"), and the chapters using it.
+type TaxonomyTerm struct {
+	Name     string
+	Slug     string
+	Chapters []*Chapter
+
+	// TaxonomySlug is the Slug of the term's taxonomy.
+	TaxonomySlug string
+}
+
+// TaxonomyByID returns the taxonomy of the book with the given ID, or nil if it is not declared.
+func (b Book) TaxonomyByID(id string) *Taxonomy {
+	for i := range b.Taxonomies {
+		if b.Taxonomies[i].ID == id {
+			return &b.Taxonomies[i]
+		}
+	}
+
+	return nil
+}
+
+// TermBySlug returns the term of the taxonomy with the given slug, or nil if no chapter uses it.
+func (t Taxonomy) TermBySlug(slug string) *TaxonomyTerm {
+	for i := range t.Terms {
+		if t.Terms[i].Slug == slug {
+			return &t.Terms[i]
+		}
+	}
+
+	return nil
+}
+
+// Terms returns the chapter's terms of the taxonomy with the given ID.
+func (c Chapter) Terms(taxonomy string) []TaxonomyTerm {
+	if c.Book == nil {
+		return nil
+	}
+
+	t := c.Book.TaxonomyByID(taxonomy)
+	if t == nil {
+		return nil
+	}
+
+	var terms []TaxonomyTerm
+	for _, name := range c.Taxonomies[taxonomy] {
+		if term := t.TermBySlug(slugify(name)); term != nil {
+			terms = append(terms, *term)
+		}
+	}
+
+	return terms
+}
+
+// normalize lowercases the taxonomy's ID, and fills in its name and slug.
+func (t *Taxonomy) normalize() {
+	t.ID = strings.ToLower(strings.TrimSpace(t.ID))
+	if t.Name == "" {
+		t.Name = t.ID
+	}
+	t.Slug = slugify(t.ID)
+}
+
+func (t Taxonomy) validate(v *validator) {
+	if t.ID == "" {
+		v.error(t.loc.field("id"), ErrTaxonomyMissingID)
+	}
+}
+
+// normalizeTaxonomies lowercases the taxonomy IDs of the chapter's terms.
+func (c *Chapter) normalizeTaxonomies() {
+	if len(c.Taxonomies) == 0 {
+		return
+	}
+
+	taxonomies := make(map[string][]string, len(c.Taxonomies))
+	for _, key := range slices.Sorted(maps.Keys(c.Taxonomies)) {
+		id := strings.ToLower(strings.TrimSpace(key))
+		taxonomies[id] = append(taxonomies[id], c.Taxonomies[key]...)
+	}
+	c.Taxonomies = taxonomies
+}
+
+// validateTaxonomies checks the chapter's terms against the book's taxonomies.
+func (c Chapter) validateTaxonomies(v *validator, loc location) {
+	for _, id := range slices.Sorted(maps.Keys(c.Taxonomies)) {
+		if c.Book != nil && c.Book.TaxonomyByID(id) == nil {
+			v.error(loc.field(id), ErrTaxonomyUnknown{ID: id})
+			continue
+		}
+
+		for i, name := range c.Taxonomies[id] {
+			if !isSafeSlug(slugify(name)) {
+				v.error(loc.field(id).join(fmt.Sprintf("[%d]", i)), ErrTaxonomyEmptyTerm{Taxonomy: id, Input: name})
+			}
+		}
+	}
+}
+
+// linkTaxonomies collects the terms of each taxonomy of the book from its chapters.
+func (b *Book) linkTaxonomies() {
+	for i := range b.Taxonomies {
+		taxonomy := &b.Taxonomies[i]
+
+		taxonomy.Terms = nil
+		for _, chapter := range b.ChaptersAndSubchapters() {
+			if chapter.IsPlaceholder() || chapter.InSplitVolume() {
+				continue
+			}
+
+			for _, name := range chapter.Taxonomies[taxonomy.ID] {
+				slug := slugify(name)
+				if !isSafeSlug(slug) {
+					continue
+				}
+
+				j := slices.IndexFunc(taxonomy.Terms, func(t TaxonomyTerm) bool { return t.Slug == slug })
+				if j < 0 {
+					taxonomy.Terms = append(taxonomy.Terms, TaxonomyTerm{Name: strings.TrimSpace(name), Slug: slug, TaxonomySlug: taxonomy.Slug})
+					j = len(taxonomy.Terms) - 1
+				}
+
+				if !slices.Contains(taxonomy.Terms[j].Chapters, chapter) {
+					taxonomy.Terms[j].Chapters = append(taxonomy.Terms[j].Chapters, chapter)
+				}
+			}
+		}
+
+		slices.SortStableFunc(taxonomy.Terms, func(x, y TaxonomyTerm) int {
+			return strings.Compare(strings.ToLower(x.Name), strings.ToLower(y.Name))
+		})
+	}
+}
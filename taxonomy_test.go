@@ -0,0 +1,101 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// taxonomiesFS returns a file system with a book at "book" of a chapter per frontMatters.
+func taxonomiesFS(taxonomies string, frontMatters ...string) fstest.MapFS {
+	fsys := fstest.MapFS{"book/pub.yml": {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\ntaxonomies:\n" + taxonomies)}}
+
+	var nav string
+	for i, frontMatter := range frontMatters {
+		name := string(rune('a'+i)) + ".md"
+		nav += "- content_file_name: " + name + "\n"
+		fsys["book/chapters/"+name] = &fstest.MapFile{Data: []byte("---\n" + frontMatter + "---\nChapter\n")}
+	}
+	fsys["book/nav.yml"] = &fstest.MapFile{Data: []byte(nav)}
+
+	return fsys
+}
+
+func TestTaxonomiesErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		taxonomies   string
+		frontMatters []string
+		want         error
+	}{
+		{"missing id", "  - name: POV\n", nil, ErrTaxonomyMissingID},
+		{"duplicate id", "  - id: pov\n  - id: POV\n", nil, ErrTaxonomyDuplicateID{}},
+		{"hidden slug", "  - id: .pov\n", nil, ErrTaxonomySlugUnsafe{}},
+		{"no letter or digit", "  - id: '???'\n", nil, ErrTaxonomySlugUnsafe{}},
+		{"duplicate slug", "  - id: point of view\n  - id: point-of-view\n", nil, ErrTaxonomyDuplicateSlug{}},
+		{"unknown taxonomy", "  - id: pov\n", []string{"taxonomies:\n  places: [Harbor]\n"}, ErrTaxonomyUnknown{}},
+		{"empty term", "  - id: pov\n", []string{"taxonomies:\n  pov: ['!!']\n"}, ErrTaxonomyEmptyTerm{}},
+		{"hidden term", "  - id: pov\n", []string{"taxonomies:\n  pov: [.mira]\n"}, ErrTaxonomyEmptyTerm{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(taxonomiesFS(tt.taxonomies, tt.frontMatters...), "book")
+
+			found := errors.Is(err, tt.want)
+			if reflect.TypeOf(tt.want).Kind() == reflect.Struct {
+				found = errors.As(err, reflect.New(reflect.TypeOf(tt.want)).Interface())
+			}
+			if !found {
+				t.Errorf("NewBookFS() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaxonomyTerms(t *testing.T) {
+	book, err := NewBookFS(taxonomiesFS("  - id: POV\n    name: Point of view\n",
+		"taxonomies:\n  pov: [Mira, Jonas]\n",
+		"taxonomies:\n  POV: [mira]\n  Pov: [Ada]\n",
+	), "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	taxonomy := book.TaxonomyByID("pov")
+	if taxonomy == nil || taxonomy.Name != "Point of view" || taxonomy.Slug != "pov" {
+		t.Fatalf("TaxonomyByID(\"pov\") = %+v", taxonomy)
+	}
+
+	type term struct {
+		name     string
+		slug     string
+		chapters []string
+	}
+	var got []term
+	for _, tt := range taxonomy.Terms {
+		var chapters []string
+		for _, chapter := range tt.Chapters {
+			chapters = append(chapters, chapter.UniqueID)
+		}
+		got = append(got, term{tt.Name, tt.Slug, chapters})
+	}
+
+	want := []term{
+		{"Ada", "ada", []string{"b"}},
+		{"Jonas", "jonas", []string{"a"}},
+		{"Mira", "mira", []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %v, want %v", got, want)
+	}
+
+	var names []string
+	for _, tt := range book.Chapters[1].Terms("pov") {
+		names = append(names, tt.Name)
+	}
+	if want := []string{"Mira", "Ada"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Terms(\"pov\") = %v, want %v", names, want)
+	}
+}
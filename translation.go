@@ -0,0 +1,225 @@
+package pub
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ErrTranslationDuplicateLanguage struct {
+	LanguageCode string
+}
+
+func (e ErrTranslationDuplicateLanguage) Error() string {
+	return fmt.Sprintf("translation: language \"%s\" is already used by the book or another translation", e.LanguageCode)
+}
+
+type ErrTranslationInvalidLanguage struct {
+	LanguageCode string
+}
+
+func (e ErrTranslationInvalidLanguage) Error() string {
+	return fmt.Sprintf("translation: language \"%s\" is not a BCP 47 language tag (e.g. \"es\" or \"pt-BR\")", e.LanguageCode)
+}
+
+type ErrTranslationReservedLanguage struct {
+	LanguageCode string
+}
+
+func (e ErrTranslationReservedLanguage) Error() string {
+	return fmt.Sprintf("translation: language \"%s\" cannot name the translation's output directory, as it is reserved for the book's pages (reserved names: %s)", e.LanguageCode, strings.Join(BookOutputDirNames, ", "))
+}
+
+// BookOutputDirNames are the output directories of a [Book], which cannot name
+// translations.
+var BookOutputDirNames = []string{"chapters", "licenses", "orders", "taxonomies", "volumes"}
+
+// languageTagPattern matches a BCP 47 language tag (e.g. "es" or "pt-BR").
+var languageTagPattern = regexp.MustCompile(`^(?:[A-Za-z]{2,8}|[Xx])(?:-[A-Za-z0-9]{1,8})*$`)
+
+// Language is an edition of a [Book] in one language: the original or a translation.
+type Language struct {
+	Code  string
+	Title string
+
+	// Dir is the path of the edition's directory relative to the current one (e.g.
+	// "es/" or "../").
+	Dir      string
+	Current  bool
+	Original bool
+
+	// Translated is the number of translated chapters, out of Total. Missing holds
+	// the unique IDs of the others.
+	Translated int
+	Total      int
+	Missing    []string
+
+	chapters map[string]bool
+}
+
+// Completeness returns the percentage of the edition's chapters that are translated.
+func (l Language) Completeness() int {
+	if l.Total == 0 {
+		return 100
+	}
+
+	return l.Translated * 100 / l.Total
+}
+
+// Alternates returns the editions of the book that the chapter is part of,
+// including the current one.
+func (c Chapter) Alternates() []Language {
+	if c.Book == nil || c.IsPlaceholder() || c.InSplitVolume() {
+		return nil
+	}
+
+	var alternates []Language
+	for _, language := range c.Book.Languages {
+		if language.chapters[c.UniqueID] {
+			alternates = append(alternates, language)
+		}
+	}
+
+	return alternates
+}
+
+// loadTranslations loads each directory of translations/ (e.g. translations/es)
+// as a translation, taking the files it is missing from the book.
+func (b *Book) loadTranslations() error {
+	b.Translations = nil
+
+	dir := path.Join(b.root, BookTranslationsDirName)
+	items, err := fs.ReadDir(b.fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[BOOK] \"%s\": %w", b.InputPath, err)
+	}
+
+	for _, item := range items {
+		if !item.IsDir() || strings.HasPrefix(item.Name(), ".") || strings.HasPrefix(item.Name(), "_") {
+			continue
+		}
+
+		var translation Book
+		translation.fsys = b.fsys
+		translation.options = b.options
+		translation.root = path.Join(dir, item.Name())
+		translation.originalRoot = b.root
+		translation.InputPath = translation.inputPathOf(translation.root)
+
+		if err := translation.load(b); err != nil {
+			return fmt.Errorf("[BOOK] \"%s\": %w", translation.InputPath, err)
+		}
+
+		if !languageTagPattern.MatchString(translation.LanguageCode) {
+			return fmt.Errorf("[BOOK] \"%s\": %w", translation.InputPath, ErrTranslationInvalidLanguage{LanguageCode: translation.LanguageCode})
+		}
+
+		if slices.Contains(BookOutputDirNames, strings.ToLower(translation.LanguageCode)) {
+			return fmt.Errorf("[BOOK] \"%s\": %w", translation.InputPath, ErrTranslationReservedLanguage{LanguageCode: translation.LanguageCode})
+		}
+
+		// language codes name output directories, which may not differ in case only
+		if strings.EqualFold(translation.LanguageCode, b.LanguageCode) || slices.ContainsFunc(b.Translations, func(t Book) bool { return strings.EqualFold(t.LanguageCode, translation.LanguageCode) }) {
+			return fmt.Errorf("[BOOK] \"%s\": %w", translation.InputPath, ErrTranslationDuplicateLanguage{LanguageCode: translation.LanguageCode})
+		}
+
+		b.Translations = append(b.Translations, translation)
+	}
+
+	b.linkTranslations()
+
+	return nil
+}
+
+// alignChapterIDs gives chapters without a unique ID that of the original's chapter
+// with the same content file.
+func (b *Book) alignChapterIDs(original *Book) {
+	ids := make(map[string]string)
+	for _, chapter := range original.ChaptersAndSubchapters() {
+		if chapter.ContentFileName != "" {
+			ids[chapter.ContentFileName] = chapter.UniqueID
+		}
+	}
+
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		if chapter.UniqueID == "" && chapter.ContentFileName != "" {
+			chapter.SetUniqueID(ids[chapter.ContentFileName])
+		}
+	}
+}
+
+// linkTranslations links the book's translations, and sets their Languages.
+func (b *Book) linkTranslations() {
+	editions := []*Book{b}
+	for i := range b.Translations {
+		translation := &b.Translations[i]
+		for _, chapter := range translation.ChaptersAndSubchapters() {
+			chapter.Book = translation
+		}
+		editions = append(editions, translation)
+	}
+
+	languages := make([]Language, len(editions))
+	for i, edition := range editions {
+		languages[i] = edition.language(i == 0)
+	}
+
+	for i, edition := range editions {
+		edition.Languages = nil
+		if len(editions) == 1 {
+			continue
+		}
+
+		for j, language := range languages {
+			language.Current = i == j
+			switch {
+			case i == j:
+				language.Dir = ""
+			case i == 0:
+				language.Dir = language.Code + "/"
+			case j == 0:
+				language.Dir = "../"
+			default:
+				language.Dir = "../" + language.Code + "/"
+			}
+			edition.Languages = append(edition.Languages, language)
+		}
+	}
+}
+
+// language returns the book as a [Language].
+func (b Book) language(original bool) Language {
+	language := Language{
+		Code:     b.LanguageCode,
+		Title:    b.Title,
+		Original: original,
+		chapters: make(map[string]bool),
+	}
+
+	for _, chapter := range b.ChaptersAndSubchapters() {
+		if chapter.IsPlaceholder() || chapter.InSplitVolume() {
+			continue
+		}
+		language.chapters[chapter.UniqueID] = true
+
+		if chapter.ContentFileName == "" {
+			continue
+		}
+
+		language.Total++
+		if original || !chapter.Untranslated {
+			language.Translated++
+		} else {
+			language.Missing = append(language.Missing, chapter.UniqueID)
+		}
+	}
+
+	return language
+}
@@ -0,0 +1,102 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// translationsFS returns a file system with a book at "book" of the translations.
+func translationsFS(translations map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{
+		"book/pub.yml":         {Data: []byte("unique_id: a\ntitle: A\nlanguage_code: en\n")},
+		"book/nav.yml":         {Data: []byte("- content_file_name: one.md\n- content_file_name: two.md\n")},
+		"book/chapters/one.md": {Data: []byte("One\n")},
+		"book/chapters/two.md": {Data: []byte("Two\n")},
+	}
+	for dir, pubYML := range translations {
+		fsys["book/translations/"+dir+"/pub.yml"] = &fstest.MapFile{Data: []byte(pubYML)}
+	}
+
+	return fsys
+}
+
+func TestTranslationLanguageErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		translations map[string]string
+		want         error
+	}{
+		{"valid", map[string]string{"es": "", "pt": "language_code: pt-BR\n"}, nil},
+		{"underscore", map[string]string{"pt_BR": ""}, ErrTranslationInvalidLanguage{LanguageCode: "pt_BR"}},
+		{"single letter", map[string]string{"e": ""}, ErrTranslationInvalidLanguage{LanguageCode: "e"}},
+		{"long subtag", map[string]string{"es": "language_code: es-123456789\n"}, ErrTranslationInvalidLanguage{LanguageCode: "es-123456789"}},
+		{"reserved", map[string]string{"volumes": ""}, ErrTranslationReservedLanguage{LanguageCode: "volumes"}},
+		{"reserved in another case", map[string]string{"fr": "language_code: Chapters\n"}, ErrTranslationReservedLanguage{LanguageCode: "Chapters"}},
+		{"original language", map[string]string{"english": "language_code: EN\n"}, ErrTranslationDuplicateLanguage{LanguageCode: "EN"}},
+		{"same language", map[string]string{"es": "", "spanish": "language_code: ES\n"}, ErrTranslationDuplicateLanguage{LanguageCode: "ES"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(translationsFS(tt.translations), "book")
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("NewBookFS() error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NewBookFS() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslationLanguages(t *testing.T) {
+	fsys := translationsFS(map[string]string{"es": "title: Libro\n"})
+	fsys["book/translations/es/chapters/one.md"] = &fstest.MapFile{Data: []byte("Uno\n")}
+
+	book, err := NewBookFS(fsys, "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	if len(book.Translations) != 1 {
+		t.Fatalf("Translations = %d, want 1", len(book.Translations))
+	}
+	es := &book.Translations[0]
+
+	tests := []struct {
+		name    string
+		edition *Book
+		want    []Language
+	}{
+		{"original", &book, []Language{
+			{Code: "en", Title: "A", Dir: "", Current: true, Original: true, Translated: 2, Total: 2},
+			{Code: "es", Title: "Libro", Dir: "es/", Translated: 1, Total: 2, Missing: []string{"two"}},
+		}},
+		{"translation", es, []Language{
+			{Code: "en", Title: "A", Dir: "../", Original: true, Translated: 2, Total: 2},
+			{Code: "es", Title: "Libro", Dir: "", Current: true, Translated: 1, Total: 2, Missing: []string{"two"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.edition.Languages
+			for i := range got {
+				got[i].chapters = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Languages = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if chapter := es.Chapters[1]; !chapter.Untranslated || chapter.Book != es {
+		t.Errorf("chapter two of the translation is Untranslated = %t, of %p, want true, of %p", chapter.Untranslated, chapter.Book, es)
+	}
+}
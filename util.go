@@ -1,5 +1,14 @@
 package pub
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
 func allChapters(chapters *[]Chapter) []*Chapter {
 	var flattened []*Chapter
 
@@ -17,3 +26,104 @@ func allChapters(chapters *[]Chapter) []*Chapter {
 
 	return flattened
 }
+
+// naturalCompare is like [strings.Compare], but compares runs of digits by value.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		ai, bi := digitPrefixLen(a), digitPrefixLen(b)
+		if ai > 0 && bi > 0 {
+			an, bn := strings.TrimLeft(a[:ai], "0"), strings.TrimLeft(b[:bi], "0")
+			if len(an) != len(bn) {
+				return len(an) - len(bn)
+			}
+			if c := strings.Compare(an, bn); c != 0 {
+				return c
+			}
+
+			a, b = a[ai:], b[bi:]
+			continue
+		}
+
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) - len(b)
+}
+
+func digitPrefixLen(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return i
+}
+
+// naturalCompareFileNames compares paths with [naturalCompare], ignoring extensions.
+func naturalCompareFileNames(a, b string) int {
+	if c := naturalCompare(strings.TrimSuffix(a, filepath.Ext(a)), strings.TrimSuffix(b, filepath.Ext(b))); c != 0 {
+		return c
+	}
+
+	return naturalCompare(a, b)
+}
+
+// prefixedError prefixes the message of err with prefix, if not empty.
+func prefixedError(prefix string, err error) error {
+	if prefix == "" {
+		return err
+	}
+
+	return fmt.Errorf("%s%w", prefix, err)
+}
+
+// baseLanguage returns the primary language of languageCode (e.g. "en" for "en-US").
+func baseLanguage(languageCode string) string {
+	language, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	language, _, _ = strings.Cut(language, "_")
+
+	return language
+}
+
+// isSafeSlug reports whether slug can name an output file: not empty nor hidden.
+func isSafeSlug(slug string) bool {
+	return slug != "" && !strings.HasPrefix(slug, ".")
+}
+
+// slugify returns s as a slug for file names and URLs (e.g. "science-fiction").
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+			continue
+		}
+		dash = true
+	}
+
+	return b.String()
+}
+
+// mappingEntries returns the entries of the mapping node, or nil if it is not one.
+func mappingEntries(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	case *ast.TagNode:
+		return mappingEntries(n.Value)
+	case *ast.AnchorNode:
+		return mappingEntries(n.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package pub
+
+import (
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"a", "a", 0},
+		{"a", "b", -1},
+		{"", "a", -1},
+		{"chapter-2", "chapter-10", -1},
+		{"chapter-10", "chapter-9", 1},
+		{"chapter-1-2", "chapter-1-10", -1},
+		{"chapter-02", "chapter-2", 0},
+		{"chapter-007", "chapter-10", -1},
+		{"2a", "2b", -1},
+		{"10", "9a", 1},
+		{"a1", "aa", -1},
+		{"Chapter-1", "chapter-1", -1},
+		{"99999999999999999999", "100000000000000000000", -1},
+	}
+
+	for _, tt := range tests {
+		if got := sign(naturalCompare(tt.a, tt.b)); got != tt.want {
+			t.Errorf("naturalCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := sign(naturalCompare(tt.b, tt.a)); got != -tt.want {
+			t.Errorf("naturalCompare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestNaturalCompareFileNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"chapter-1.md", "chapter-1-1.md", -1},
+		{"chapter-2.md", "chapter-10.md", -1},
+		{"a.md", "a.txt", -1},
+		{"part-2/a.md", "part-10/a.md", -1},
+	}
+
+	for _, tt := range tests {
+		if got := sign(naturalCompareFileNames(tt.a, tt.b)); got != tt.want {
+			t.Errorf("naturalCompareFileNames(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,73 @@
+package pub
+
+import (
+	"fmt"
+	"slices"
+)
+
+// VolumeBooks returns a sub-book for each top-level volume of the book, with the
+// book's metadata and the volume's title, content and chapters.
+func (b *Book) VolumeBooks() ([]*Book, error) {
+	var volumes []*Book
+	for i := range b.Chapters {
+		volume := &b.Chapters[i]
+		if !volume.IsVolume() {
+			continue
+		}
+
+		raw, err := volume.Content.Bytes()
+		if err != nil {
+			return volumes, fmt.Errorf("[CHAPTER] \"%s\": %w", volume.InputPath, err)
+		}
+
+		sub := *b
+		sub.ParentBook = b
+		sub.UniqueID = volume.UniqueID
+		sub.Title = volume.Title
+		sub.Subtitle = volume.Subtitle
+		sub.TitlesAlternate = nil
+		sub.Content = Content{Raw: raw}
+		sub.IDs = volume.IDs
+		sub.Series = nil
+		sub.SplitVolumes = false
+		sub.Withheld = nil
+		sub.ReadingOrders = nil
+		sub.Translations = nil
+		sub.Languages = nil
+
+		if len(volume.Authors) > 0 {
+			sub.Authors = volume.Authors
+		}
+
+		if volume.LanguageCode != "" {
+			sub.LanguageCode = volume.LanguageCode
+		}
+
+		sub.Chapters = cloneChapters(volume.Chapters)
+		setChapterHierarchy(sub.Chapters, nil)
+		for _, chapter := range sub.ChaptersAndSubchapters() {
+			chapter.Book = &sub
+		}
+		linkChapters(sub.Chapters)
+		sub.Taxonomies = slices.Clone(b.Taxonomies)
+		sub.linkTaxonomies()
+
+		volumes = append(volumes, &sub)
+	}
+
+	return volumes, nil
+}
+
+// cloneChapters returns a deep copy of chapters, whose hierarchy can be changed.
+func cloneChapters(chapters []Chapter) []Chapter {
+	cloned := slices.Clone(chapters)
+	for i := range cloned {
+		cloned[i].Chapters = cloneChapters(cloned[i].Chapters)
+		cloned[i].Content = cloned[i].Content.clone()
+		cloned[i].AuthorsNotePrefix = cloned[i].AuthorsNotePrefix.clone()
+		cloned[i].AuthorsNoteSuffix = cloned[i].AuthorsNoteSuffix.clone()
+		cloned[i].readingOrders = nil
+	}
+
+	return cloned
+}
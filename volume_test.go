@@ -0,0 +1,86 @@
+package pub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// volumesFS returns a file system with a book at "book" of a volume per uniqueIDs.
+func volumesFS(splitVolumes bool, uniqueIDs ...string) fstest.MapFS {
+	pubYML := "unique_id: a\ntitle: A\nlanguage_code: en\n"
+	if splitVolumes {
+		pubYML += "split_volumes: true\n"
+	}
+
+	fsys := fstest.MapFS{"book/pub.yml": {Data: []byte(pubYML)}}
+	var nav string
+	for i, uniqueID := range uniqueIDs {
+		name := string(rune('a'+i)) + ".md"
+		nav += "- unique_id: '" + uniqueID + "'\n  title: Volume\n  kind: volume\n  chapters:\n    - content_file_name: " + name + "\n"
+		fsys["book/chapters/"+name] = &fstest.MapFile{Data: []byte("Chapter\n")}
+	}
+	fsys["book/nav.yml"] = &fstest.MapFile{Data: []byte(nav)}
+
+	return fsys
+}
+
+func TestVolumeSlugs(t *testing.T) {
+	tests := []struct {
+		name         string
+		splitVolumes bool
+		uniqueIDs    []string
+		want         error
+	}{
+		{"distinct", true, []string{"Book One", "book two"}, nil},
+		{"hidden", true, []string{".hidden"}, ErrChapterVolumeSlugUnsafe{}},
+		{"no letter or digit", true, []string{"???"}, ErrChapterVolumeSlugUnsafe{}},
+		{"same slug", true, []string{"book one", "book-one"}, ErrChapterVolumeDuplicateSlug{}},
+		{"not split", false, []string{"book one", "book-one"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBookFS(volumesFS(tt.splitVolumes, tt.uniqueIDs...), "book")
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("NewBookFS() error = %v", err)
+				}
+				return
+			}
+
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("NewBookFS() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVolumeBooks(t *testing.T) {
+	book, err := NewBookFS(volumesFS(true, "Book One", "book: two"), "book")
+	if err != nil {
+		t.Fatalf("NewBookFS() error = %v", err)
+	}
+
+	volumes, err := book.VolumeBooks()
+	if err != nil {
+		t.Fatalf("VolumeBooks() error = %v", err)
+	}
+
+	var slugs []string
+	for i, volume := range volumes {
+		slugs = append(slugs, volume.Slug())
+		if volume.ParentBook != &book || volume.Slug() != book.Chapters[i].Slug() {
+			t.Errorf("volumes[%d] = %q of %p, want %q of %p", i, volume.Slug(), volume.ParentBook, book.Chapters[i].Slug(), &book)
+		}
+		if chapter := volume.Chapters[0]; chapter.Book != volume || chapter.Parent != nil || chapter.Number() != "1" {
+			t.Errorf("volumes[%d].Chapters[0] = %q numbered %q, want a top-level chapter of the volume numbered \"1\"", i, chapter.UniqueID, chapter.Number())
+		}
+	}
+
+	if want := []string{"book-one", "book-two"}; !reflect.DeepEqual(slugs, want) {
+		t.Errorf("VolumeBooks() slugs = %v, want %v", slugs, want)
+	}
+}